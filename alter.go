@@ -0,0 +1,397 @@
+package sb
+
+import "bytes"
+
+// Algorithm 是在线 DDL 的 ALGORITHM 选项
+type Algorithm string
+
+const (
+	AlgorithmDefault Algorithm = "DEFAULT"
+	AlgorithmInstant Algorithm = "INSTANT"
+	AlgorithmInplace Algorithm = "INPLACE"
+	AlgorithmCopy    Algorithm = "COPY"
+)
+
+// Lock 是在线 DDL 的 LOCK 选项
+type Lock string
+
+const (
+	LockDefault   Lock = "DEFAULT"
+	LockNone      Lock = "NONE"
+	LockShared    Lock = "SHARED"
+	LockExclusive Lock = "EXCLUSIVE"
+)
+
+type onlineDDL struct {
+	algorithm Algorithm
+	lock      Lock
+}
+
+// writeSQL 输出 ALGORITHM 和 LOCK 选项，prefix 是与前面内容的分隔符，sep 是两个选项之间的分隔符：
+// ALTER TABLE 中用逗号，CREATE/DROP INDEX 中用空格
+func (o onlineDDL) writeSQL(buf *bytes.Buffer, prefix, sep string) {
+	if o.algorithm != "" {
+		buf.WriteString(prefix)
+		buf.WriteString("ALGORITHM=")
+		buf.WriteString(string(o.algorithm))
+		prefix = sep
+	}
+	if o.lock != "" {
+		buf.WriteString(prefix)
+		buf.WriteString("LOCK=")
+		buf.WriteString(string(o.lock))
+	}
+}
+
+type alterAction uint8
+
+const (
+	addColumnAction alterAction = iota
+	modifyColumnAction
+	changeColumnAction
+	renameColumnAction
+	dropColumnAction
+	addIndexAction
+	dropIndexAction
+)
+
+type alterSpec struct {
+	action     alterAction
+	column     Column
+	name       string // CHANGE/RENAME COLUMN 的新列名或索引名
+	definition string
+	position   string // FIRST 或 AFTER `col`
+	kind       IndexKind
+	columns    []Column
+}
+
+func (s *alterSpec) writeSQL(buf *bytes.Buffer) {
+	switch s.action {
+	case addColumnAction, modifyColumnAction, changeColumnAction:
+		switch s.action {
+		case addColumnAction:
+			buf.WriteString("ADD COLUMN ")
+		case modifyColumnAction:
+			buf.WriteString("MODIFY COLUMN ")
+		default:
+			buf.WriteString("CHANGE COLUMN ")
+		}
+		s.column.WriteSQL(buf, NoAlias)
+		if s.action == changeColumnAction {
+			buf.WriteString(" `")
+			buf.WriteString(s.name)
+			buf.WriteByte('`')
+		}
+		buf.WriteByte(' ')
+		buf.WriteString(s.definition)
+		buf.WriteString(s.position)
+	case renameColumnAction:
+		buf.WriteString("RENAME COLUMN ")
+		s.column.WriteSQL(buf, NoAlias)
+		buf.WriteString(" TO `")
+		buf.WriteString(s.name)
+		buf.WriteByte('`')
+	case dropColumnAction:
+		buf.WriteString("DROP COLUMN ")
+		s.column.WriteSQL(buf, NoAlias)
+	case addIndexAction:
+		buf.WriteString("ADD ")
+		writeIndexDefinition(buf, &IndexSchema{Name: s.name, Kind: s.kind, Columns: columnNames(s.columns)})
+	case dropIndexAction:
+		if s.name == "" {
+			buf.WriteString("DROP PRIMARY KEY")
+		} else {
+			buf.WriteString("DROP INDEX `")
+			buf.WriteString(s.name)
+			buf.WriteByte('`')
+		}
+	}
+}
+
+type AlterTableQuery struct {
+	table AnyTable
+	specs []alterSpec
+	onlineDDL
+}
+
+// AlterTable 生成 ALTER TABLE 语句，多个修改按调用顺序用逗号连接：
+//
+//	AlterTable(u).AddColumn(u.Age, "int NOT NULL DEFAULT 0").After(u.Name).Algorithm(AlgorithmInplace).Lock(LockNone)
+//	// ALTER TABLE `user` ADD COLUMN `age` int NOT NULL DEFAULT 0 AFTER `name`, ALGORITHM=INPLACE, LOCK=NONE
+func AlterTable(table AnyTable) *AlterTableQuery {
+	return &AlterTableQuery{table: table}
+}
+
+// AddColumn 添加列，definition 是列名之后的定义，如 "int NOT NULL DEFAULT 0"
+func (q *AlterTableQuery) AddColumn(column Column, definition string) *AlterTableQuery {
+	q.specs = append(q.specs, alterSpec{action: addColumnAction, column: column, definition: definition})
+	return q
+}
+
+func (q *AlterTableQuery) ModifyColumn(column Column, definition string) *AlterTableQuery {
+	q.specs = append(q.specs, alterSpec{action: modifyColumnAction, column: column, definition: definition})
+	return q
+}
+
+// ChangeColumn 修改列名和列定义
+func (q *AlterTableQuery) ChangeColumn(column Column, name, definition string) *AlterTableQuery {
+	q.specs = append(q.specs, alterSpec{action: changeColumnAction, column: column, name: name, definition: definition})
+	return q
+}
+
+// First 将上一个 AddColumn、ModifyColumn 或 ChangeColumn 的列放在第一列
+func (q *AlterTableQuery) First() *AlterTableQuery {
+	if len(q.specs) > 0 {
+		q.specs[len(q.specs)-1].position = " FIRST"
+	}
+	return q
+}
+
+// After 将上一个 AddColumn、ModifyColumn 或 ChangeColumn 的列放在 column 之后
+func (q *AlterTableQuery) After(column Column) *AlterTableQuery {
+	if len(q.specs) > 0 {
+		q.specs[len(q.specs)-1].position = " AFTER `" + column.name + "`"
+	}
+	return q
+}
+
+func (q *AlterTableQuery) RenameColumn(column Column, name string) *AlterTableQuery {
+	q.specs = append(q.specs, alterSpec{action: renameColumnAction, column: column, name: name})
+	return q
+}
+
+func (q *AlterTableQuery) DropColumn(column Column) *AlterTableQuery {
+	q.specs = append(q.specs, alterSpec{action: dropColumnAction, column: column})
+	return q
+}
+
+// AddIndex 添加索引，kind 为 PrimaryKey 时忽略 name
+func (q *AlterTableQuery) AddIndex(kind IndexKind, name string, columns ...Column) *AlterTableQuery {
+	if kind == PrimaryKey {
+		name = ""
+	}
+	q.specs = append(q.specs, alterSpec{action: addIndexAction, kind: kind, name: name, columns: columns})
+	return q
+}
+
+// DropIndex 删除索引，name 为空时删除主键
+func (q *AlterTableQuery) DropIndex(name string) *AlterTableQuery {
+	q.specs = append(q.specs, alterSpec{action: dropIndexAction, name: name})
+	return q
+}
+
+func (q *AlterTableQuery) Algorithm(algorithm Algorithm) *AlterTableQuery {
+	q.algorithm = algorithm
+	return q
+}
+
+func (q *AlterTableQuery) Lock(lock Lock) *AlterTableQuery {
+	q.lock = lock
+	return q
+}
+
+func (q *AlterTableQuery) WriteSQL(buf *bytes.Buffer) {
+	buf.WriteString("ALTER TABLE ")
+	writeTableName(buf, q.table)
+	for i := range q.specs {
+		if i == 0 {
+			buf.WriteByte(' ')
+		} else {
+			buf.WriteString(", ")
+		}
+		q.specs[i].writeSQL(buf)
+	}
+	if len(q.specs) == 0 {
+		q.onlineDDL.writeSQL(buf, " ", ", ")
+	} else {
+		q.onlineDDL.writeSQL(buf, ", ", ", ")
+	}
+}
+
+func (q *AlterTableQuery) String() string {
+	buf := pool.Get().(*bytes.Buffer)
+	buf.Reset()
+
+	q.WriteSQL(buf)
+
+	sql := sqlString(buf)
+	pool.Put(buf)
+	return sql
+}
+
+type CreateIndexQuery struct {
+	name    string
+	kind    IndexKind
+	table   AnyTable
+	columns []Column
+	onlineDDL
+}
+
+// CreateIndex 生成 CREATE INDEX 语句，可以用 Unique() 或 Fulltext() 指定索引类型
+func CreateIndex(name string, table AnyTable, columns ...Column) *CreateIndexQuery {
+	return &CreateIndexQuery{name: name, table: table, columns: columns}
+}
+
+func (q *CreateIndexQuery) Unique() *CreateIndexQuery {
+	q.kind = UniqueIndex
+	return q
+}
+
+func (q *CreateIndexQuery) Fulltext() *CreateIndexQuery {
+	q.kind = FulltextIndex
+	return q
+}
+
+func (q *CreateIndexQuery) Algorithm(algorithm Algorithm) *CreateIndexQuery {
+	q.algorithm = algorithm
+	return q
+}
+
+func (q *CreateIndexQuery) Lock(lock Lock) *CreateIndexQuery {
+	q.lock = lock
+	return q
+}
+
+func (q *CreateIndexQuery) WriteSQL(buf *bytes.Buffer) {
+	switch q.kind {
+	case UniqueIndex:
+		buf.WriteString("CREATE UNIQUE INDEX `")
+	case FulltextIndex:
+		buf.WriteString("CREATE FULLTEXT INDEX `")
+	default:
+		buf.WriteString("CREATE INDEX `")
+	}
+	buf.WriteString(q.name)
+	buf.WriteString("` ON ")
+	writeTableName(buf, q.table)
+	buf.WriteString(" (")
+	writeColumnNames(buf, columnNames(q.columns))
+	buf.WriteByte(')')
+	q.onlineDDL.writeSQL(buf, " ", " ")
+}
+
+func (q *CreateIndexQuery) String() string {
+	buf := pool.Get().(*bytes.Buffer)
+	buf.Reset()
+
+	q.WriteSQL(buf)
+
+	sql := sqlString(buf)
+	pool.Put(buf)
+	return sql
+}
+
+type DropIndexQuery struct {
+	name  string
+	table AnyTable
+	onlineDDL
+}
+
+func DropIndex(name string, table AnyTable) *DropIndexQuery {
+	return &DropIndexQuery{name: name, table: table}
+}
+
+func (q *DropIndexQuery) Algorithm(algorithm Algorithm) *DropIndexQuery {
+	q.algorithm = algorithm
+	return q
+}
+
+func (q *DropIndexQuery) Lock(lock Lock) *DropIndexQuery {
+	q.lock = lock
+	return q
+}
+
+func (q *DropIndexQuery) WriteSQL(buf *bytes.Buffer) {
+	buf.WriteString("DROP INDEX `")
+	buf.WriteString(q.name)
+	buf.WriteString("` ON ")
+	writeTableName(buf, q.table)
+	q.onlineDDL.writeSQL(buf, " ", " ")
+}
+
+func (q *DropIndexQuery) String() string {
+	buf := pool.Get().(*bytes.Buffer)
+	buf.Reset()
+
+	q.WriteSQL(buf)
+
+	sql := sqlString(buf)
+	pool.Put(buf)
+	return sql
+}
+
+type TruncateQuery struct {
+	table AnyTable
+}
+
+func Truncate(table AnyTable) *TruncateQuery {
+	return &TruncateQuery{table: table}
+}
+
+func (q *TruncateQuery) WriteSQL(buf *bytes.Buffer) {
+	buf.WriteString("TRUNCATE TABLE ")
+	writeTableName(buf, q.table)
+}
+
+func (q *TruncateQuery) String() string {
+	buf := pool.Get().(*bytes.Buffer)
+	buf.Reset()
+
+	q.WriteSQL(buf)
+
+	sql := sqlString(buf)
+	pool.Put(buf)
+	return sql
+}
+
+type tableRename struct {
+	from AnyTable
+	to   string
+}
+
+type RenameTableQuery struct {
+	renames []tableRename
+}
+
+// RenameTable 生成 RENAME TABLE 语句，新表名与原表在同一个库中，多个重命名在同一个语句中原子地执行，可以用来交换表：
+//
+//	RenameTable(a, "a_old").Rename(b, "a") // RENAME TABLE `a` TO `a_old`, `b` TO `a`
+func RenameTable(from AnyTable, to string) *RenameTableQuery {
+	return &RenameTableQuery{renames: []tableRename{{from: from, to: to}}}
+}
+
+func (q *RenameTableQuery) Rename(from AnyTable, to string) *RenameTableQuery {
+	q.renames = append(q.renames, tableRename{from: from, to: to})
+	return q
+}
+
+func (q *RenameTableQuery) WriteSQL(buf *bytes.Buffer) {
+	buf.WriteString("RENAME TABLE ")
+	for i, rename := range q.renames {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		writeTableName(buf, rename.from)
+		buf.WriteString(" TO ")
+		writeTableName(buf, Table{schema: rename.from.getSchema(), name: rename.to})
+	}
+}
+
+func (q *RenameTableQuery) String() string {
+	buf := pool.Get().(*bytes.Buffer)
+	buf.Reset()
+
+	q.WriteSQL(buf)
+
+	sql := sqlString(buf)
+	pool.Put(buf)
+	return sql
+}
+
+func columnNames(columns []Column) []string {
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = column.name
+	}
+	return names
+}
@@ -0,0 +1,74 @@
+package sb
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestAlterTable(t *testing.T) {
+	a := New[AccountTable]("a")
+	u := New[UserTable]("")
+	d := New[DeptTable]("")
+
+	tests := []struct {
+		query    fmt.Stringer
+		expected string
+	}{
+		{
+			query:    AlterTable(a).AddColumn(a.Bio, "text").After(a.Name).Algorithm(AlgorithmInplace).Lock(LockNone),
+			expected: "ALTER TABLE `account` ADD COLUMN `bio` text AFTER `name`, ALGORITHM=INPLACE, LOCK=NONE",
+		},
+		{
+			query: AlterTable(a).
+				AddColumn(a.TenantID, "int NOT NULL").First().
+				ModifyColumn(a.Name, "varchar(128) NOT NULL DEFAULT ''").
+				ChangeColumn(a.Bio, "intro", "text").
+				RenameColumn(a.UpdatedAt, "modified_at").
+				DropColumn(a.ID),
+			expected: "ALTER TABLE `account` ADD COLUMN `tenant_id` int NOT NULL FIRST, MODIFY COLUMN `name` varchar(128) NOT NULL DEFAULT '', " +
+				"CHANGE COLUMN `bio` `intro` text, RENAME COLUMN `updated_at` TO `modified_at`, DROP COLUMN `id`",
+		},
+		{
+			query: AlterTable(a).DropIndex("").AddIndex(PrimaryKey, "ignored", a.ID, a.TenantID).
+				DropIndex("uk_name").AddIndex(UniqueIndex, "uk_name", a.Name).AddIndex(NormalIndex, "idx_bio", a.Bio),
+			expected: "ALTER TABLE `account` DROP PRIMARY KEY, ADD PRIMARY KEY (`id`, `tenant_id`), " +
+				"DROP INDEX `uk_name`, ADD UNIQUE KEY `uk_name` (`name`), ADD KEY `idx_bio` (`bio`)",
+		},
+		{
+			query:    AlterTable(a).Algorithm(AlgorithmCopy).Lock(LockShared),
+			expected: "ALTER TABLE `account` ALGORITHM=COPY, LOCK=SHARED",
+		},
+		{
+			query:    CreateIndex("idx_name", u, u.Name, u.ID),
+			expected: "CREATE INDEX `idx_name` ON `user` (`name`, `id`)",
+		},
+		{
+			query:    CreateIndex("uk_name", u, u.Name).Unique().Algorithm(AlgorithmInplace).Lock(LockNone),
+			expected: "CREATE UNIQUE INDEX `uk_name` ON `user` (`name`) ALGORITHM=INPLACE LOCK=NONE",
+		},
+		{
+			query:    CreateIndex("ft_bio", a, a.Bio).Fulltext().Lock(LockShared),
+			expected: "CREATE FULLTEXT INDEX `ft_bio` ON `account` (`bio`) LOCK=SHARED",
+		},
+		{
+			query:    DropIndex("idx_name", u).Algorithm(AlgorithmInplace).Lock(LockNone),
+			expected: "DROP INDEX `idx_name` ON `user` ALGORITHM=INPLACE LOCK=NONE",
+		},
+		{
+			query:    Truncate(u),
+			expected: "TRUNCATE TABLE `user`",
+		},
+		{
+			query:    RenameTable(u, "user_old").Rename(d, "user"),
+			expected: "RENAME TABLE `user` TO `user_old`, `dept` TO `user`",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			if got := test.query.String(); got != test.expected {
+				t.Errorf("got %s, want %s", got, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package sb
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+)
+
+// Value 是绑定了值的占位符，输出为 ?，值会在 Build() 时按顺序放入参数中
+type Value struct {
+	value any
+}
+
+func Val(value any) Value {
+	return Value{value: value}
+}
+
+func (v Value) WriteSQL(buf *bytes.Buffer, aliasMode AliasMode) {
+	buf.WriteByte('?')
+}
+
+func (v Value) appendArgs(args []any) []any {
+	return append(args, v.value)
+}
+
+// Param 是命名的占位符，输出为 ?，需要在 Build() 或 Compiled.Args() 中用 sql.Named() 绑定值
+type Param string
+
+func (p Param) WriteSQL(buf *bytes.Buffer, aliasMode AliasMode) {
+	buf.WriteByte('?')
+}
+
+func (p Param) appendArgs(args []any) []any {
+	return append(args, p)
+}
+
+// placeholder 表示一个未绑定值的 ?，需要由 Build() 的参数按顺序填充
+type placeholder struct{}
+
+// argsAppender 由可能包含参数的表达式实现，需要按输出 SQL 的顺序追加参数
+type argsAppender interface {
+	appendArgs(args []any) []any
+}
+
+func appendArgs(args []any, e any) []any {
+	if a, ok := e.(argsAppender); ok {
+		return a.appendArgs(args)
+	}
+	return args
+}
+
+func appendPlaceholders(args []any, count int) []any {
+	for i := 0; i < count; i++ {
+		args = append(args, placeholder{})
+	}
+	return args
+}
+
+// countMarks 返回 s 中不在引号内的 ? 的数量
+func countMarks(s string) int {
+	count := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote != '`' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '?':
+			count++
+		}
+	}
+	return count
+}
+
+// Statement 是生成的 SQL 和对应的参数
+type Statement struct {
+	SQL  string
+	Args []any
+}
+
+// With 返回使用新参数的语句，用于复用已生成的 SQL
+func (s Statement) With(args ...any) Statement {
+	return Statement{SQL: s.SQL, Args: args}
+}
+
+// bindArgs 将 slots 复制到 dst 中，并用 args 填充未绑定值的占位符：
+// sql.NamedArg 按名字绑定 Param，其他值按顺序绑定 ?。dst 可以与 slots 共用底层数组
+func bindArgs(dst []any, slots []any, args []any) ([]any, error) {
+	n := 0 // 下一个按顺序绑定的参数
+	for _, slot := range slots {
+		switch slot := slot.(type) {
+		case placeholder:
+			for n < len(args) {
+				if _, ok := args[n].(sql.NamedArg); !ok {
+					break
+				}
+				n++
+			}
+			if n == len(args) {
+				return nil, fmt.Errorf("sb: expected %d args, got %d", countPlaceholders(slots), countPositional(args))
+			}
+			dst = append(dst, args[n])
+			n++
+		case Param:
+			value, ok := namedArg(args, string(slot))
+			if !ok {
+				return nil, fmt.Errorf("sb: missing named arg %s", slot)
+			}
+			dst = append(dst, value)
+		default:
+			dst = append(dst, slot)
+		}
+	}
+	for ; n < len(args); n++ {
+		if _, ok := args[n].(sql.NamedArg); !ok {
+			return nil, fmt.Errorf("sb: expected %d args, got %d", countPlaceholders(slots), countPositional(args))
+		}
+	}
+	return dst, nil
+}
+
+func namedArg(args []any, name string) (any, bool) {
+	for _, arg := range args {
+		if arg, ok := arg.(sql.NamedArg); ok && arg.Name == name {
+			return arg.Value, true
+		}
+	}
+	return nil, false
+}
+
+// countPlaceholders 和 countPositional 仅用于生成错误信息
+func countPlaceholders(slots []any) int {
+	count := 0
+	for _, slot := range slots {
+		if _, ok := slot.(placeholder); ok {
+			count++
+		}
+	}
+	return count
+}
+
+func countPositional(args []any) int {
+	count := 0
+	for _, arg := range args {
+		if _, ok := arg.(sql.NamedArg); !ok {
+			count++
+		}
+	}
+	return count
+}
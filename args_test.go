@@ -0,0 +1,116 @@
+package sb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCountMarks(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected int
+	}{
+		{"", 0},
+		{"?", 1},
+		{"? + ?", 2},
+		{"'?' + ?", 1},
+		{`"a\"?" + ?`, 1},
+		{"`?` + ?", 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.s, func(t *testing.T) {
+			if got := countMarks(test.s); got != test.expected {
+				t.Errorf("got %d, want %d", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestBuild(t *testing.T) {
+	u := New[UserTable]("u")
+	du := New[DeptUserTable]("du")
+
+	tests := []struct {
+		query interface {
+			Build(args ...any) (Statement, error)
+		}
+		args     []any
+		expected Statement
+	}{
+		{
+			query:    u.Select().Where(u.ID.Eq(Val(1))),
+			expected: Statement{SQL: "SELECT * FROM `user` WHERE `id` = ?", Args: []any{1}},
+		},
+		{
+			query:    u.Select().Where(u.ID.Eq(PH)),
+			args:     []any{1},
+			expected: Statement{SQL: "SELECT * FROM `user` WHERE `id` = ?", Args: []any{1}},
+		},
+		{
+			query: Select(u, Func("IFNULL", du.DeptID, Val(0))).
+				FromJoin(u.LeftJoin(du, u.ID.Eq(du.UserID))).
+				Where(And(u.Name.Eq(PH), u.ID.In(Select(du.UserID).From(du).Where(du.DeptID.Gt(Val(2)))), u.ID.Lt(PH))),
+			args: []any{"a", 3},
+			expected: Statement{
+				SQL:  "SELECT `u`.*, IFNULL(`du`.`deptid`, ?) FROM `user` AS `u` LEFT JOIN `dept_user` AS `du` ON `u`.`id` = `du`.`userid` WHERE `u`.`name` = ? AND `u`.`id` IN (SELECT `du`.`userid` FROM `dept_user` AS `du` WHERE `du`.`deptid` > ?) AND `u`.`id` < ?",
+				Args: []any{0, "a", 2, 3},
+			},
+		},
+		{
+			query:    Insert(u).Columns(u.ID, u.Name),
+			args:     []any{1, "a"},
+			expected: Statement{SQL: "INSERT INTO `user` (`id`, `name`) VALUES (?, ?)", Args: []any{1, "a"}},
+		},
+		{
+			query:    Insert(u).Columns(u.ID, u.Name).Values(Val(1), PH).OnDuplicateKeyUpdate(u.Name.Assign(Val("b"))),
+			args:     []any{"a"},
+			expected: Statement{SQL: "INSERT INTO `user` (`id`, `name`) VALUES (?, ?) ON DUPLICATE KEY UPDATE `name`=?", Args: []any{1, "a", "b"}},
+		},
+		{
+			query:    Insert(u).Columns(u.ID, u.Name).NamedValues(),
+			expected: Statement{SQL: "INSERT INTO `user` (`id`, `name`) VALUES (:id, :name)"},
+		},
+		{
+			query:    Update(u).Set(u.ID.Assign(u.ID.Plus(Val(1))), u.Name.Assign(PH)).Where(u.ID.Eq(Val(2))),
+			args:     []any{"a"},
+			expected: Statement{SQL: "UPDATE `user` SET `id`=`id`+?, `name`=? WHERE `id` = ?", Args: []any{1, "a", 2}},
+		},
+		{
+			query:    Delete(u).Where(u.ID.In(PH)),
+			args:     []any{[]int{1, 2}},
+			expected: Statement{SQL: "DELETE `user` WHERE `id` IN (?)", Args: []any{[]int{1, 2}}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expected.SQL, func(t *testing.T) {
+			got, err := test.query.Build(test.args...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("got %v, want %v", got, test.expected)
+			}
+		})
+	}
+
+	if _, err := u.Select().Where(u.ID.Eq(PH)).Build(); err == nil {
+		t.Error("missing args not reported")
+	}
+	if _, err := u.Select().Where(u.ID.Eq(PH)).Build(1, 2); err == nil {
+		t.Error("extra args not reported")
+	}
+}
+
+func TestStatementWith(t *testing.T) {
+	u := New[UserTable]("")
+	s, err := u.Select().Where(u.ID.Eq(PH)).Build(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s2 := s.With(2)
+	if s2.SQL != s.SQL || !reflect.DeepEqual(s2.Args, []any{2}) || !reflect.DeepEqual(s.Args, []any{1}) {
+		t.Errorf("got %v and %v", s, s2)
+	}
+}
@@ -17,6 +17,10 @@ func (a Assignment) WriteSQL(buf *bytes.Buffer, aliasMode AliasMode) {
 	}
 }
 
+func (a Assignment) appendArgs(args []any) []any {
+	return appendArgs(args, a.value)
+}
+
 type Assignments []Assignment
 
 func (a Assignments) WriteSQL(buf *bytes.Buffer, aliasMode AliasMode) {
@@ -31,3 +35,10 @@ func (a Assignments) WriteSQL(buf *bytes.Buffer, aliasMode AliasMode) {
 		}
 	}
 }
+
+func (a Assignments) appendArgs(args []any) []any {
+	for _, assignment := range a {
+		args = assignment.appendArgs(args)
+	}
+	return args
+}
@@ -0,0 +1,353 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"src.yizhisec.com/backend-cbb/sb"
+)
+
+const (
+	defaultImportPath = "src.yizhisec.com/backend-cbb/sb"
+	header            = "// Code generated by sbgen.\n// 重新生成时会按列新增或删除字段，已有的列字段（包括改过的名字、类型和 tag）、其他字段和声明都会保留，可以在此文件中添加方法。\n\n"
+)
+
+// commonAcronyms 是生成字段名时需要全部大写的缩写词
+var commonAcronyms = []string{
+	"ACL", "API", "ASCII", "CPU", "CSS", "DNS", "EOF", "GUID", "HTML", "HTTP", "HTTPS", "ID", "IP", "JSON", "LHS", "QPS",
+	"RAM", "RHS", "RPC", "SLA", "SMTP", "SQL", "SSH", "TCP", "TLS", "TTL", "UDP", "UI", "UID", "UUID", "URI", "URL", "UTF8", "VM", "XML",
+}
+
+type config struct {
+	pkg        string
+	importPath string
+	suffix     string
+	trimPrefix string
+	acronyms   []string
+}
+
+func (c config) qualifier() string {
+	if c.pkg == "sb" {
+		return ""
+	}
+	return "sb."
+}
+
+// camelCase 将 user_id 转换为 UserID
+func (c config) camelCase(name string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool {
+		return !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z')
+	}) {
+		upper := strings.ToUpper(part)
+		if c.isAcronym(upper) {
+			b.WriteString(upper)
+		} else {
+			b.WriteString(strings.ToUpper(part[:1]))
+			b.WriteString(part[1:])
+		}
+	}
+	if b.Len() == 0 || (b.String()[0] >= '0' && b.String()[0] <= '9') { // 无法作为标识符
+		return "X" + b.String()
+	}
+	return b.String()
+}
+
+func (c config) isAcronym(s string) bool {
+	for _, acronym := range c.acronyms {
+		if strings.ToUpper(acronym) == s {
+			return true
+		}
+	}
+	for _, acronym := range commonAcronyms {
+		if acronym == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (c config) typeName(table string) string {
+	return c.camelCase(strings.TrimPrefix(table, c.trimPrefix)) + c.suffix
+}
+
+// generate 生成 Go 代码，existing 是输出文件原有的内容：已有的列字段按 db tag 对应到列，
+// 保留它的名字、类型、tag 和注释，只为新增的列生成字段并删除已不存在的列的字段；
+// 其他字段、声明和 import 也会被保留，生成的字段名会避开保留的字段名
+func generate(tables []*sb.TableSchema, existing []byte, cfg config) ([]byte, error) {
+	generated := map[string]bool{}
+	for _, table := range tables {
+		generated[cfg.typeName(table.Name)] = true
+	}
+	prev, err := parseExisting(existing, generated, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(header)
+	fmt.Fprintf(&buf, "package %s\n\n", cfg.pkg)
+	var imports []string
+	if cfg.qualifier() != "" {
+		imports = append(imports, strconv.Quote(cfg.importPath))
+	}
+	imports = append(imports, prev.imports...)
+	switch len(imports) {
+	case 0:
+	case 1:
+		fmt.Fprintf(&buf, "import %s\n\n", imports[0])
+	default:
+		fmt.Fprintf(&buf, "import (\n%s\n)\n\n", strings.Join(imports, "\n"))
+	}
+
+	q := cfg.qualifier()
+	for _, table := range tables {
+		typeName := cfg.typeName(table.Name)
+		if table.Comment != "" {
+			fmt.Fprintf(&buf, "// %s %s\n", typeName, singleLine(table.Comment))
+		}
+		fmt.Fprintf(&buf, "type %s struct {\n", typeName)
+		if field, ok := prev.tables[typeName]; ok {
+			buf.WriteString(field)
+			buf.WriteByte('\n')
+		} else {
+			fmt.Fprintf(&buf, "%sTable `db:%s`\n", q, strconv.Quote(table.Name))
+		}
+
+		used := map[string]bool{}
+		for _, name := range prev.names[typeName] {
+			used[name] = true
+		}
+		for _, column := range table.Columns {
+			if field, ok := prev.columns[typeName][column.Name]; ok {
+				for _, name := range field.names {
+					used[name] = true
+				}
+			}
+		}
+		for _, column := range table.Columns {
+			if field, ok := prev.columns[typeName][column.Name]; ok {
+				buf.WriteString(field.source)
+				buf.WriteByte('\n')
+				continue
+			}
+			fieldName := cfg.camelCase(column.Name)
+			for used[fieldName] {
+				fieldName += "_"
+			}
+			used[fieldName] = true
+
+			fmt.Fprintf(&buf, "%s %sColumn `db:%s`", fieldName, q, strconv.Quote(column.Name))
+			if column.Comment != "" {
+				fmt.Fprintf(&buf, " // %s", singleLine(column.Comment))
+			}
+			buf.WriteByte('\n')
+		}
+		for _, field := range prev.fields[typeName] {
+			buf.WriteString(field)
+			buf.WriteByte('\n')
+		}
+		buf.WriteString("}\n\n")
+	}
+	for _, decl := range prev.decls {
+		buf.WriteString(decl)
+		buf.WriteString("\n\n")
+	}
+
+	code, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w", err)
+	}
+	return code, nil
+}
+
+func singleLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// existingCode 是已有代码中需要保留的部分，map 的 key 为类型名
+type existingCode struct {
+	tables  map[string]string                    // 内嵌的 Table 字段的源码
+	columns map[string]map[string]existingColumn // 列字段，key 为 db tag 中的列名
+	fields  map[string][]string                  // 自定义字段（即非 Table、列类型的字段）的源码
+	names   map[string][]string                  // 自定义字段的名字
+	imports []string                             // 除 sb 以外的 import
+	decls   []string                             // 生成的类型以外的声明，包括方法、函数、变量、常量和其他类型
+}
+
+// existingColumn 是已有的 Column、TypedColumn 类型的字段
+type existingColumn struct {
+	source string // 包括注释的源码
+	names  []string
+}
+
+// parseExisting 解析已有代码，generated 是本次会生成的类型名
+func parseExisting(src []byte, generated map[string]bool, cfg config) (*existingCode, error) {
+	code := &existingCode{
+		tables:  map[string]string{},
+		columns: map[string]map[string]existingColumn{},
+		fields:  map[string][]string{},
+		names:   map[string][]string{},
+	}
+	if len(src) == 0 {
+		return code, nil
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("parse existing file: %w", err)
+	}
+	text := func(start, end token.Pos) string {
+		return string(src[fset.Position(start).Offset:fset.Position(end).Offset])
+	}
+
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok {
+			fn := decl.(*ast.FuncDecl)
+			start := fn.Pos()
+			if fn.Doc != nil {
+				start = fn.Doc.Pos()
+			}
+			code.decls = append(code.decls, text(start, fn.End()))
+			continue
+		}
+
+		switch gen.Tok {
+		case token.IMPORT:
+			for _, spec := range gen.Specs {
+				imp := spec.(*ast.ImportSpec)
+				if imp.Name == nil && imp.Path.Value == strconv.Quote(cfg.importPath) {
+					continue
+				}
+				code.imports = append(code.imports, text(imp.Pos(), imp.End()))
+			}
+			continue
+		case token.TYPE:
+			if code.collectFields(gen, generated, text) {
+				continue
+			}
+		}
+		start := gen.Pos()
+		if gen.Doc != nil {
+			start = gen.Doc.Pos()
+		}
+		code.decls = append(code.decls, text(start, gen.End()))
+	}
+	return code, nil
+}
+
+// collectFields 收集 type 声明中生成的类型已有的字段，其余的类型单独保留，返回是否已处理该声明
+func (c *existingCode) collectFields(gen *ast.GenDecl, generated map[string]bool, text func(start, end token.Pos) string) bool {
+	found := false
+	for _, spec := range gen.Specs {
+		if generated[spec.(*ast.TypeSpec).Name.Name] {
+			found = true
+		}
+	}
+	if !found {
+		return false
+	}
+
+	for _, spec := range gen.Specs {
+		typeSpec := spec.(*ast.TypeSpec)
+		if !generated[typeSpec.Name.Name] {
+			start := typeSpec.Pos()
+			if typeSpec.Doc != nil {
+				start = typeSpec.Doc.Pos()
+			}
+			c.decls = append(c.decls, "type "+text(start, typeSpec.End()))
+			continue
+		}
+		st, ok := typeSpec.Type.(*ast.StructType)
+		if !ok { // 会被生成的 struct 替换
+			continue
+		}
+		name := typeSpec.Name.Name
+		for _, field := range st.Fields.List {
+			start, end := field.Pos(), field.End()
+			if field.Doc != nil {
+				start = field.Doc.Pos()
+			}
+			if field.Comment != nil {
+				end = field.Comment.End()
+			}
+			switch typ := fieldType(field); {
+			case typ == "Table" && len(field.Names) == 0:
+				c.tables[name] = text(start, end)
+			case typ == "Column" || typ == "TypedColumn":
+				if c.columns[name] == nil {
+					c.columns[name] = map[string]existingColumn{}
+				}
+				c.columns[name][dbTag(field)] = existingColumn{source: text(start, end), names: fieldNames(field)}
+			default:
+				c.fields[name] = append(c.fields[name], text(start, end))
+				c.names[name] = append(c.names[name], fieldNames(field)...)
+			}
+		}
+	}
+	return true
+}
+
+// fieldNames 返回字段的名字，嵌入字段的名字为类型名
+func fieldNames(field *ast.Field) []string {
+	if len(field.Names) == 0 {
+		typ := field.Type
+		if star, ok := typ.(*ast.StarExpr); ok {
+			typ = star.X
+		}
+		switch typ := typ.(type) {
+		case *ast.Ident:
+			return []string{typ.Name}
+		case *ast.SelectorExpr:
+			return []string{typ.Sel.Name}
+		}
+		return nil
+	}
+	names := make([]string, len(field.Names))
+	for i, name := range field.Names {
+		names[i] = name.Name
+	}
+	return names
+}
+
+// fieldType 返回字段类型的名字，不包括包名、指针和类型参数，如 sb.TypedColumn[int64] 返回 TypedColumn
+func fieldType(field *ast.Field) string {
+	typ := field.Type
+	switch t := typ.(type) {
+	case *ast.IndexExpr:
+		typ = t.X
+	case *ast.IndexListExpr:
+		typ = t.X
+	}
+	switch t := typ.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	}
+	return ""
+}
+
+// dbTag 返回字段 db tag 中的列名，没有 tag 时与 sb 的默认规则相同，使用小写的字段名
+func dbTag(field *ast.Field) string {
+	if field.Tag != nil {
+		if tag, err := strconv.Unquote(field.Tag.Value); err == nil {
+			if name := reflect.StructTag(tag).Get("db"); name != "" {
+				return name
+			}
+		}
+	}
+	if len(field.Names) == 0 {
+		return ""
+	}
+	return strings.ToLower(field.Names[0].Name)
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"src.yizhisec.com/backend-cbb/sb"
+)
+
+const testDDL = "CREATE TABLE `t_user` (\n" +
+	"  `id` bigint NOT NULL AUTO_INCREMENT,\n" +
+	"  `user_name` varchar(64) NOT NULL COMMENT 'login\nname',\n" +
+	"  `avatar_url` varchar(255),\n" +
+	"  `sku_id` int,\n" +
+	"  `2fa` tinyint(1),\n" +
+	"  PRIMARY KEY (`id`)\n" +
+	") COMMENT='用户';\n" +
+	"CREATE TABLE `t_dept` (`id` int);\n"
+
+func TestCamelCase(t *testing.T) {
+	cfg := config{acronyms: []string{"sku"}}
+	tests := map[string]string{
+		"id":         "ID",
+		"user_id":    "UserID",
+		"avatar_url": "AvatarURL",
+		"sku_id":     "SKUID",
+		"userName":   "UserName",
+		"http-proxy": "HTTPProxy",
+		"2fa":        "X2fa",
+		"_":          "X",
+	}
+	for name, expected := range tests {
+		if got := cfg.camelCase(name); got != expected {
+			t.Errorf("%s: got %s, want %s", name, got, expected)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	tables, err := sb.ParseDDL(testDDL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := config{pkg: "model", importPath: defaultImportPath, suffix: "Table", trimPrefix: "t_", acronyms: []string{"SKU"}}
+
+	code, err := generate(tables, nil, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := header + `package model
+
+import "src.yizhisec.com/backend-cbb/sb"
+
+// UserTable 用户
+type UserTable struct {
+	sb.Table  ` + "`db:\"t_user\"`" + `
+	ID        sb.Column ` + "`db:\"id\"`" + `
+	UserName  sb.Column ` + "`db:\"user_name\"`" + ` // login name
+	AvatarURL sb.Column ` + "`db:\"avatar_url\"`" + `
+	SKUID     sb.Column ` + "`db:\"sku_id\"`" + `
+	X2fa      sb.Column ` + "`db:\"2fa\"`" + `
+}
+
+type DeptTable struct {
+	sb.Table ` + "`db:\"t_dept\"`" + `
+	ID       sb.Column ` + "`db:\"id\"`" + `
+}
+`
+	if string(code) != expected {
+		t.Errorf("got:\n%s\nwant:\n%s", code, expected)
+	}
+
+	cfg.pkg = "sb"
+	code, err = generate(tables[1:], nil, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = header + "package sb\n\ntype DeptTable struct {\n\tTable `db:\"t_dept\"`\n\tID    Column `db:\"id\"`\n}\n"
+	if string(code) != expected {
+		t.Errorf("got:\n%s\nwant:\n%s", code, expected)
+	}
+}
+
+func TestRegenerate(t *testing.T) {
+	dir := t.TempDir()
+	in := filepath.Join(dir, "schema.sql")
+	out := filepath.Join(dir, "tables.go")
+	if err := os.WriteFile(in, []byte(testDDL), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config{pkg: "model", importPath: defaultImportPath, suffix: "Table", trimPrefix: "t_"}
+	if err := run(in, out, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	// 添加自定义字段，并删除一列
+	code, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edited := string(code[:len(code)-len("}\n")]) + "\t// 自定义字段\n\tExtra string `json:\"extra\"` // 注释\n\tOld sb.Column `db:\"old\"`\n}\n"
+	if err = os.WriteFile(out, []byte(edited), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err = run(in, out, cfg); err != nil {
+		t.Fatal(err)
+	}
+	first, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = run(in, out, cfg); err != nil {
+		t.Fatal(err)
+	}
+	second, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(first) != string(second) {
+		t.Errorf("not idempotent:\n%s\n%s", first, second)
+	}
+
+	expected := "type DeptTable struct {\n\tsb.Table `db:\"t_dept\"`\n\tID       sb.Column `db:\"id\"`\n\t// 自定义字段\n\tExtra string `json:\"extra\"` // 注释\n}\n"
+	if got := string(second[len(second)-len(expected):]); got != expected {
+		t.Errorf("got:\n%s\nwant:\n%s", got, expected)
+	}
+}
+
+func TestRegenerateMerge(t *testing.T) {
+	tables, err := sb.ParseDDL(testDDL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := config{pkg: "model", importPath: defaultImportPath, suffix: "Table", trimPrefix: "t_"}
+	existing := header + `package model
+
+import (
+	"time"
+
+	"src.yizhisec.com/backend-cbb/sb"
+)
+
+type DeptTable struct {
+	sb.Table ` + "`db:\"t_dept\"`" + `
+	ID       sb.Column ` + "`db:\"id\"`" + `
+	Ids      []int
+	Created  time.Time
+	*Extra
+}
+
+// Extra 自定义类型
+type Extra struct{}
+
+// Name 返回表名
+func (t DeptTable) Name() string {
+	return "dept"
+}
+
+const version = 1
+`
+	// 新增的 ids、created 列与保留的字段重名
+	tables[1].Columns = append(tables[1].Columns, &sb.ColumnSchema{Name: "ids"}, &sb.ColumnSchema{Name: "created"}, &sb.ColumnSchema{Name: "extra"})
+	code, err := generate(tables[1:], []byte(existing), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := header + `package model
+
+import (
+	"src.yizhisec.com/backend-cbb/sb"
+	"time"
+)
+
+type DeptTable struct {
+	sb.Table ` + "`db:\"t_dept\"`" + `
+	ID       sb.Column ` + "`db:\"id\"`" + `
+	Ids_     sb.Column ` + "`db:\"ids\"`" + `
+	Created_ sb.Column ` + "`db:\"created\"`" + `
+	Extra_   sb.Column ` + "`db:\"extra\"`" + `
+	Ids      []int
+	Created  time.Time
+	*Extra
+}
+
+// Extra 自定义类型
+type Extra struct{}
+
+// Name 返回表名
+func (t DeptTable) Name() string {
+	return "dept"
+}
+
+const version = 1
+`
+	if string(code) != expected {
+		t.Errorf("got:\n%s\nwant:\n%s", code, expected)
+	}
+
+	again, err := generate(tables[1:], code, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(again) != string(code) {
+		t.Errorf("not idempotent:\n%s\n%s", code, again)
+	}
+}
+
+func TestRegenerateColumns(t *testing.T) {
+	tables, err := sb.ParseDDL(testDDL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := config{pkg: "model", importPath: defaultImportPath, suffix: "Table", trimPrefix: "t_"}
+	// 改过名字、类型和 tag 的列字段，以及已不存在的列
+	existing := header + `package model
+
+import "src.yizhisec.com/backend-cbb/sb"
+
+type UserTable struct {
+	sb.Table ` + "`db:\"t_user\" ddl:\"comment:用户\"`" + `
+	// 主键
+	ID     sb.TypedColumn[int64] ` + "`db:\"id\" ddl:\"primary key\"`" + `
+	Login  sb.Column             ` + "`db:\"user_name\" sb:\"tenant\"`" + ` // 登录名
+	Old    sb.Column             ` + "`db:\"old\"`" + `
+	SkuID  string
+}
+`
+	code, err := generate(tables[:1], []byte(existing), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := header + `package model
+
+import "src.yizhisec.com/backend-cbb/sb"
+
+// UserTable 用户
+type UserTable struct {
+	sb.Table ` + "`db:\"t_user\" ddl:\"comment:用户\"`" + `
+	// 主键
+	ID        sb.TypedColumn[int64] ` + "`db:\"id\" ddl:\"primary key\"`" + `
+	Login     sb.Column             ` + "`db:\"user_name\" sb:\"tenant\"`" + ` // 登录名
+	AvatarURL sb.Column             ` + "`db:\"avatar_url\"`" + `
+	SkuID_    sb.Column             ` + "`db:\"sku_id\"`" + `
+	X2fa      sb.Column             ` + "`db:\"2fa\"`" + `
+	SkuID     string
+}
+`
+	if string(code) != expected {
+		t.Errorf("got:\n%s\nwant:\n%s", code, expected)
+	}
+
+	again, err := generate(tables[:1], code, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(again) != string(code) {
+		t.Errorf("not idempotent:\n%s\n%s", code, again)
+	}
+}
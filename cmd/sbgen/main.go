@@ -0,0 +1,88 @@
+// sbgen 将 CREATE TABLE 语句转换为 sb 的表结构定义，例如：
+//
+//	mysqldump --no-data db | sbgen -pkg model -out model/tables.go
+//
+// 重新生成时会保留输出文件中已有的非 Column 字段和其他声明。
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"src.yizhisec.com/backend-cbb/sb"
+)
+
+func main() {
+	var (
+		in       = flag.String("in", "", "SQL 文件，默认从标准输入读取")
+		out      = flag.String("out", "", "输出的 Go 文件，默认输出到标准输出")
+		pkg      = flag.String("pkg", "model", "包名")
+		sbPath   = flag.String("sb", defaultImportPath, "sb 的 import 路径，包名为 sb 时不导入")
+		suffix   = flag.String("suffix", "Table", "类型名的后缀")
+		prefix   = flag.String("trim-prefix", "", "生成类型名时去掉的表名前缀")
+		acronyms = flag.String("acronyms", "", "额外的缩写词，用逗号分隔，如 SKU,OS")
+	)
+	flag.Parse()
+
+	if err := run(*in, *out, config{
+		pkg:        *pkg,
+		importPath: *sbPath,
+		suffix:     *suffix,
+		trimPrefix: *prefix,
+		acronyms:   splitList(*acronyms),
+	}); err != nil {
+		fmt.Fprintln(os.Stderr, "sbgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(in, out string, cfg config) error {
+	var (
+		data []byte
+		err  error
+	)
+	if in == "" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(in)
+	}
+	if err != nil {
+		return err
+	}
+
+	tables, err := sb.ParseDDL(string(data))
+	if err != nil {
+		return err
+	}
+
+	var existing []byte
+	if out != "" {
+		existing, err = os.ReadFile(out)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	code, err := generate(tables, existing, cfg)
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		_, err = os.Stdout.Write(code)
+		return err
+	}
+	return os.WriteFile(out, code, 0o644)
+}
+
+func splitList(s string) []string {
+	var list []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -31,13 +31,14 @@ func (c Column) WriteSQL(buf *bytes.Buffer, aliasMode AliasMode) {
 		fallthrough
 	case UseAlias:
 		if c.table != nil { // 正常情况都 != nil，除非手动构建
-			alias := c.table.getAlias()
-			if alias == "" {
-				alias = c.table.getName()
+			if alias := c.table.getAlias(); alias == "" {
+				writeTableName(buf, c.table)
+			} else {
+				buf.WriteByte('`')
+				buf.WriteString(alias)
+				buf.WriteByte('`')
 			}
-			buf.WriteByte('`')
-			buf.WriteString(alias)
-			buf.WriteString("`.`")
+			buf.WriteString(".`")
 			buf.WriteString(c.name)
 
 			if c.alias != "" {
@@ -0,0 +1,83 @@
+package sb
+
+import (
+	"bytes"
+	"strings"
+)
+
+// sanitizeComment 将 */ 替换为 * /，避免提前结束注释
+func sanitizeComment(s string) string {
+	return strings.ReplaceAll(s, "*/", "* /")
+}
+
+// writeComments 输出 /* comment */ ，每条注释后有一个空格，用于语句的开头
+func writeComments(buf *bytes.Buffer, comments []string) {
+	for _, comment := range comments {
+		buf.WriteString("/* ")
+		buf.WriteString(sanitizeComment(comment))
+		buf.WriteString(" */ ")
+	}
+}
+
+// writeHints 输出 /*+ hint1 hint2 */ ，用于紧跟在 SELECT、INSERT、UPDATE、DELETE 之后
+func writeHints(buf *bytes.Buffer, hints []string) {
+	if len(hints) == 0 {
+		return
+	}
+	buf.WriteString("/*+ ")
+	for i, hint := range hints {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+		buf.WriteString(sanitizeComment(hint))
+	}
+	buf.WriteString(" */ ")
+}
+
+// Comment 在语句开头添加注释，如 /* trace_id=abc */，可以多次调用，内容中的 */ 会被替换为 * /
+func (q *SelectQuery) Comment(comment string) *SelectQuery {
+	q.comments = append(q.comments[:len(q.comments):len(q.comments)], comment) // 不影响 Copy 得到的语句
+	return q
+}
+
+// Hint 添加优化器提示，输出在 SELECT 之后，如 Hint("MAX_EXECUTION_TIME(1000)") 输出 SELECT /*+ MAX_EXECUTION_TIME(1000) */ ...
+func (q *SelectQuery) Hint(hints ...string) *SelectQuery {
+	q.hints = append(q.hints[:len(q.hints):len(q.hints)], hints...)
+	return q
+}
+
+// Comment 在语句开头添加注释，规则与 SelectQuery.Comment 相同
+func (q *InsertQuery) Comment(comment string) *InsertQuery {
+	q.comments = append(q.comments, comment)
+	return q
+}
+
+// Hint 添加优化器提示，输出在 INSERT 之后
+func (q *InsertQuery) Hint(hints ...string) *InsertQuery {
+	q.hints = append(q.hints, hints...)
+	return q
+}
+
+// Comment 在语句开头添加注释，规则与 SelectQuery.Comment 相同
+func (q *UpdateQuery) Comment(comment string) *UpdateQuery {
+	q.comments = append(q.comments, comment)
+	return q
+}
+
+// Hint 添加优化器提示，输出在 UPDATE 之后
+func (q *UpdateQuery) Hint(hints ...string) *UpdateQuery {
+	q.hints = append(q.hints, hints...)
+	return q
+}
+
+// Comment 在语句开头添加注释，规则与 SelectQuery.Comment 相同
+func (q *DeleteQuery) Comment(comment string) *DeleteQuery {
+	q.comments = append(q.comments, comment)
+	return q
+}
+
+// Hint 添加优化器提示，输出在 DELETE 之后，软删除时输出在 UPDATE 之后
+func (q *DeleteQuery) Hint(hints ...string) *DeleteQuery {
+	q.hints = append(q.hints, hints...)
+	return q
+}
@@ -0,0 +1,68 @@
+package sb
+
+import "testing"
+
+func TestCommentAndHint(t *testing.T) {
+	u := New[UserTable]("u")
+	p := New[PostTable]("p")
+
+	tests := []struct {
+		query    interface{ String() string }
+		expected string
+	}{
+		{
+			query:    u.Select().Where(u.ID.Eq(PH)).Comment("trace_id=abc").Hint("MAX_EXECUTION_TIME(1000)", "INDEX(user idx_name)"),
+			expected: "/* trace_id=abc */ SELECT /*+ MAX_EXECUTION_TIME(1000) INDEX(user idx_name) */ * FROM `user` WHERE `id` = ?",
+		},
+		{
+			query:    u.Select().Comment("a").Comment("b"),
+			expected: "/* a */ /* b */ SELECT * FROM `user`",
+		},
+		{
+			// 不能提前结束注释
+			query:    u.Select().Comment("x */ DROP TABLE user; /*").Hint("NO_ICP(u) */ UNION SELECT 1"),
+			expected: "/* x * / DROP TABLE user; /* */ SELECT /*+ NO_ICP(u) * / UNION SELECT 1 */ * FROM `user`",
+		},
+		{
+			query:    u.Select().Where(u.ID.In(Select(u.ID).From(u).Hint("NO_INDEX(u)"))),
+			expected: "SELECT * FROM `user` WHERE `id` IN (SELECT /*+ NO_INDEX(u) */ `id` FROM `user`)",
+		},
+		{
+			query:    u.Insert(u.ID).Ignore().Comment("c").Hint("SET_VAR(foreign_key_checks=OFF)"),
+			expected: "/* c */ INSERT /*+ SET_VAR(foreign_key_checks=OFF) */ IGNORE INTO `user` (`id`) VALUES (?)",
+		},
+		{
+			query:    u.Insert(u.ID).Hint("SET_VAR(foreign_key_checks=OFF)"),
+			expected: "INSERT /*+ SET_VAR(foreign_key_checks=OFF) */ INTO `user` (`id`) VALUES (?)",
+		},
+		{
+			query:    u.Update(u.Name.Assign(PH)).Comment("c").Hint("BKA(user)"),
+			expected: "/* c */ UPDATE /*+ BKA(user) */ `user` SET `name`=?",
+		},
+		{
+			query:    u.Delete().Comment("c").Hint("MAX_EXECUTION_TIME(10)"),
+			expected: "/* c */ DELETE /*+ MAX_EXECUTION_TIME(10) */ `user`",
+		},
+		{
+			query:    p.Delete().Comment("c").Hint("MAX_EXECUTION_TIME(10)"),
+			expected: "/* c */ UPDATE /*+ MAX_EXECUTION_TIME(10) */ `post` SET `deleted_at`=NOW() WHERE `deleted_at` IS NULL",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			if got := test.query.String(); got != test.expected {
+				t.Errorf("got %s, want %s", got, test.expected)
+			}
+		})
+	}
+
+	q := u.Select().Comment("a")
+	c := q.Copy().Comment("b").Hint("BKA(user)")
+	if got := q.String(); got != "/* a */ SELECT * FROM `user`" {
+		t.Errorf("got %s", got)
+	}
+	if got := c.String(); got != "/* a */ /* b */ SELECT /*+ BKA(user) */ * FROM `user`" {
+		t.Errorf("got %s", got)
+	}
+}
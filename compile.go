@@ -0,0 +1,46 @@
+package sb
+
+// Compiled 是已生成的 SQL，用于跳过热点语句的重复生成
+type Compiled struct {
+	sql         string
+	intercepted string // Compile 时经过 Interceptor 处理的 SQL，由 SQL 返回
+	slots       []any  // 绑定的值、placeholder 或 Param
+	err         error  // 如严格模式下没有设置租户、Interceptor 拒绝，在 Args 中返回
+
+	interception interception
+}
+
+// newCompiled 在 Compile 时调用一次 Interceptor（Args 为 nil），拒绝时记录在 err 中
+func newCompiled(sql string, slots []any, err error, interception interception) *Compiled {
+	c := &Compiled{sql: sql, slots: slots, err: err, interception: interception}
+	if err == nil {
+		c.intercepted, c.err = interception.interceptSQL(sql)
+	}
+	return c
+}
+
+// SQL 返回 Compile 时经过 Interceptor 处理的 SQL，语句无效或 Interceptor 拒绝时返回空字符串，与 String 相同
+func (c *Compiled) SQL() string {
+	return c.intercepted
+}
+
+// Args 返回参数列表：args 会按顺序填充未绑定值的 ?，sql.NamedArg 会按名字绑定 Param，Val 绑定的值保持不变
+func (c *Compiled) Args(args ...any) ([]any, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return bindArgs(make([]any, 0, len(c.slots)), c.slots, args)
+}
+
+// Bind 返回可以直接执行的语句，参数规则与 Args 相同，返回前会调用 Interceptor
+func (c *Compiled) Bind(args ...any) (Statement, error) {
+	bound, err := c.Args(args...)
+	if err != nil {
+		return Statement{}, err
+	}
+	sql, bound, err := c.interception.intercept(c.sql, bound)
+	if err != nil {
+		return Statement{}, err
+	}
+	return Statement{SQL: sql, Args: bound}, nil
+}
@@ -0,0 +1,100 @@
+package sb
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+func TestCompile(t *testing.T) {
+	u := New[UserTable]("")
+	q := u.Select().Where(And(u.ID.Eq(PH), u.Name.Eq(Param("name")), u.ID.Ne(Val(0)), u.Name.Ne(Param("name"))))
+	c := q.Compile()
+	q.Limit(1) // 不影响已编译的语句
+
+	expected := "SELECT * FROM `user` WHERE `id` = ? AND `name` = ? AND `id` != ? AND `name` != ?"
+	if got := c.SQL(); got != expected {
+		t.Errorf("got %s, want %s", got, expected)
+	}
+
+	tests := []struct {
+		args     []any
+		expected []any
+	}{
+		{
+			args:     []any{1, sql.Named("name", "a")},
+			expected: []any{1, "a", 0, "a"},
+		},
+		{
+			args:     []any{sql.Named("name", "b"), 2, sql.Named("other", "c")},
+			expected: []any{2, "b", 0, "b"},
+		},
+	}
+	for _, test := range tests {
+		args, err := c.Args(test.args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(args, test.expected) {
+			t.Errorf("got %v, want %v", args, test.expected)
+		}
+	}
+
+	s, err := c.Bind(3, sql.Named("name", "c"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.SQL != expected || !reflect.DeepEqual(s.Args, []any{3, "c", 0, "c"}) {
+		t.Errorf("got %v", s)
+	}
+
+	for _, args := range [][]any{
+		{1},
+		{sql.Named("name", "a")},
+		{1, 2, sql.Named("name", "a")},
+	} {
+		if _, err = c.Args(args...); err == nil {
+			t.Errorf("%v: error not reported", args)
+		}
+	}
+
+	if got := Insert(u).Columns(u.ID, u.Name).Compile().SQL(); got != "INSERT INTO `user` (`id`, `name`) VALUES (?, ?)" {
+		t.Errorf("got %s", got)
+	}
+	if got := Update(u).Set(u.Name.Assign(Param("name"))).Compile().SQL(); got != "UPDATE `user` SET `name`=?" {
+		t.Errorf("got %s", got)
+	}
+	if got := Delete(u).Where(u.ID.Eq(PH)).Compile().SQL(); got != "DELETE `user` WHERE `id` = ?" {
+		t.Errorf("got %s", got)
+	}
+}
+
+func BenchmarkSelectString(b *testing.B) {
+	u := New[UserTable]("")
+	q := u.Select().Where(u.ID.Eq(PH))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = q.String()
+	}
+}
+
+func BenchmarkSelectBuild(b *testing.B) {
+	u := New[UserTable]("")
+	q := u.Select().Where(u.ID.Eq(PH))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = q.Build("id")
+	}
+}
+
+func BenchmarkCompiledArgs(b *testing.B) {
+	u := New[UserTable]("")
+	c := u.Select().Where(u.ID.Eq(PH)).Compile()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = c.Args("id")
+	}
+}
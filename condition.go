@@ -108,6 +108,11 @@ func (c Condition) WriteSQL(buf *bytes.Buffer, aliasMode AliasMode) {
 	}
 }
 
+func (c Condition) appendArgs(args []any) []any {
+	args = appendArgs(args, c.lv)
+	return appendArgs(args, c.rv)
+}
+
 type Conditions struct {
 	conditions []Cond
 	op         boolOp
@@ -183,6 +188,13 @@ func (c Conditions) WriteSQL(buf *bytes.Buffer, aliasMode AliasMode) {
 	}
 }
 
+func (c Conditions) appendArgs(args []any) []any {
+	for _, cond := range c.conditions {
+		args = appendArgs(args, cond)
+	}
+	return args
+}
+
 // TODO: 合并相同类型
 func And(conditions ...Cond) Conditions {
 	return Conditions{
@@ -204,3 +216,26 @@ func Not(cond Cond) Conditions {
 		op:         not,
 	}
 }
+
+// andWhere 返回 where AND cond 作为新的顶层条件，不会修改 where
+func andWhere(where Cond, cond Cond) Conditions {
+	var conditions []Cond
+	switch where := where.(type) {
+	case nil:
+	case Conditions:
+		if where.op == and {
+			conditions = make([]Cond, 0, len(where.conditions)+1)
+			conditions = append(conditions, where.conditions...)
+		} else {
+			where.isTopLevel = false
+			conditions = []Cond{where}
+		}
+	default:
+		conditions = []Cond{where}
+	}
+	return Conditions{
+		conditions: append(conditions, cond),
+		op:         and,
+		isTopLevel: true,
+	}
+}
@@ -0,0 +1,21 @@
+package sb
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Queryer 可以是 *sql.DB、*sql.Tx、*sql.Conn 或 sqlx 的对应类型
+type Queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// Preparer 可以是 *sql.DB、*sql.Tx、*sql.Conn 或 sqlx 的对应类型
+type Preparer interface {
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+// Execer 可以是 *sql.DB、*sql.Tx、*sql.Conn 或 sqlx 的对应类型
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
@@ -0,0 +1,322 @@
+package sb
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type tagOption struct {
+	key   string
+	value string
+}
+
+// parseTagOptions 解析 "key:value;flag" 形式的 tag，key 会转为小写
+func parseTagOptions(tag string) []tagOption {
+	var options []tagOption
+	for _, item := range strings.Split(tag, ";") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(item, ":")
+		key = strings.ToLower(strings.Join(strings.Fields(key), " "))
+		options = append(options, tagOption{key: key, value: strings.TrimSpace(value)})
+	}
+	return options
+}
+
+// SchemaOf 根据 T 的 ddl tag 得到表结构：
+//
+//	type UserTable struct {
+//		Table `db:"user" ddl:"engine:InnoDB;charset:utf8mb4;comment:用户"`
+//		ID    Column `db:"id" ddl:"type:bigint unsigned;primary key;auto_increment"`
+//		Name  Column `db:"name" ddl:"type:varchar(64);not null;default:'';unique:uk_name;comment:用户名"`
+//	}
+//
+// 列可用的选项有 type、not null、null、default、auto_increment、on update、charset、collate、comment、
+// primary key、unique、index 和 fulltext，其中 unique、index 和 fulltext 可以指定索引名，同名索引的列按字段顺序组合；
+// 表可用的选项有 engine、charset、collate 和 comment
+func SchemaOf[T AnyTable]() (*TableSchema, error) {
+	return schemaOf(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+func schemaOf(rt reflect.Type) (*TableSchema, error) {
+	meta := tableMetaOf(rt)
+	if meta == nil {
+		return nil, fmt.Errorf("sb: %s is not a table struct", rt)
+	}
+
+	schema := &TableSchema{Schema: meta.Schema, Name: meta.Name}
+	for _, option := range parseTagOptions(meta.Tag.Get("ddl")) {
+		switch option.key {
+		case "engine":
+			schema.Engine = option.value
+		case "charset":
+			schema.Charset = option.value
+		case "collate":
+			schema.Collate = option.value
+		case "comment":
+			schema.Comment = option.value
+		default:
+			return nil, fmt.Errorf("sb: unknown ddl option %q of table %s", option.key, schema.Name)
+		}
+	}
+
+	var primaryKey *IndexSchema
+	var indexes []*IndexSchema
+	addToIndex := func(kind IndexKind, name, column string) {
+		for _, index := range indexes {
+			if index.Name == name {
+				index.Columns = append(index.Columns, column)
+				return
+			}
+		}
+		indexes = append(indexes, &IndexSchema{Name: name, Kind: kind, Columns: []string{column}})
+	}
+
+	for _, c := range meta.Columns {
+		column := &ColumnSchema{Name: c.Name, Nullable: true}
+		for _, option := range parseTagOptions(c.Tag.Get("ddl")) {
+			switch option.key {
+			case "type":
+				column.Type = option.value
+			case "not null":
+				column.Nullable = false
+			case "null":
+				column.Nullable = true
+			case "default":
+				value := option.value
+				column.Default = &value
+			case "auto_increment":
+				column.AutoIncrement = true
+			case "on update":
+				column.OnUpdate = option.value
+			case "charset":
+				column.Charset = option.value
+			case "collate":
+				column.Collate = option.value
+			case "comment":
+				column.Comment = option.value
+			case "primary key":
+				column.Nullable = false
+				if primaryKey == nil {
+					primaryKey = &IndexSchema{Kind: PrimaryKey}
+				}
+				primaryKey.Columns = append(primaryKey.Columns, column.Name)
+			case "unique", "index", "fulltext":
+				name := option.value
+				if name == "" {
+					name = column.Name
+				}
+				kind := NormalIndex
+				if option.key == "unique" {
+					kind = UniqueIndex
+				} else if option.key == "fulltext" {
+					kind = FulltextIndex
+				}
+				addToIndex(kind, name, column.Name)
+			default:
+				return nil, fmt.Errorf("sb: unknown ddl option %q of column %s.%s", option.key, schema.Name, column.Name)
+			}
+		}
+		if column.Type == "" {
+			return nil, fmt.Errorf("sb: column %s.%s has no type", schema.Name, column.Name)
+		}
+		schema.Columns = append(schema.Columns, column)
+	}
+
+	if primaryKey != nil {
+		schema.Indexes = append(schema.Indexes, primaryKey)
+	}
+	schema.Indexes = append(schema.Indexes, indexes...)
+	return schema, nil
+}
+
+type CreateTableQuery struct {
+	schema      *TableSchema
+	ifNotExists bool
+}
+
+// CreateTable 根据 T 的 ddl tag 生成建表语句，tag 有误时会 panic，可以用 SchemaOf 检查
+func CreateTable[T AnyTable]() *CreateTableQuery {
+	schema, err := SchemaOf[T]()
+	if err != nil {
+		panic(err)
+	}
+	return &CreateTableQuery{schema: schema}
+}
+
+// CreateTableFromSchema 根据 schema 生成建表语句
+func CreateTableFromSchema(schema *TableSchema) *CreateTableQuery {
+	return &CreateTableQuery{schema: schema}
+}
+
+func (q *CreateTableQuery) IfNotExists() *CreateTableQuery {
+	q.ifNotExists = true
+	return q
+}
+
+func (q *CreateTableQuery) WriteSQL(buf *bytes.Buffer) {
+	if q.ifNotExists {
+		buf.WriteString("CREATE TABLE IF NOT EXISTS ")
+	} else {
+		buf.WriteString("CREATE TABLE ")
+	}
+	writeTableName(buf, Table{schema: q.schema.Schema, name: q.schema.Name})
+	buf.WriteString(" (")
+	for i, column := range q.schema.Columns {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		writeColumnDefinition(buf, column)
+	}
+	for _, index := range q.schema.Indexes {
+		buf.WriteString(", ")
+		writeIndexDefinition(buf, index)
+	}
+	buf.WriteByte(')')
+
+	if q.schema.Engine != "" {
+		buf.WriteString(" ENGINE=")
+		buf.WriteString(q.schema.Engine)
+	}
+	if q.schema.Charset != "" {
+		buf.WriteString(" DEFAULT CHARSET=")
+		buf.WriteString(q.schema.Charset)
+	}
+	if q.schema.Collate != "" {
+		buf.WriteString(" COLLATE=")
+		buf.WriteString(q.schema.Collate)
+	}
+	if q.schema.Comment != "" {
+		buf.WriteString(" COMMENT=")
+		writeStringLiteral(buf, q.schema.Comment)
+	}
+}
+
+func (q *CreateTableQuery) String() string {
+	buf := pool.Get().(*bytes.Buffer)
+	buf.Reset()
+
+	q.WriteSQL(buf)
+
+	sql := sqlString(buf)
+	pool.Put(buf)
+	return sql
+}
+
+type DropTableQuery struct {
+	tables   []AnyTable
+	ifExists bool
+}
+
+func DropTable(tables ...AnyTable) *DropTableQuery {
+	return &DropTableQuery{tables: tables}
+}
+
+func (q *DropTableQuery) IfExists() *DropTableQuery {
+	q.ifExists = true
+	return q
+}
+
+func (q *DropTableQuery) WriteSQL(buf *bytes.Buffer) {
+	if q.ifExists {
+		buf.WriteString("DROP TABLE IF EXISTS ")
+	} else {
+		buf.WriteString("DROP TABLE ")
+	}
+	for i, table := range q.tables {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		writeTableName(buf, table)
+	}
+}
+
+func (q *DropTableQuery) String() string {
+	buf := pool.Get().(*bytes.Buffer)
+	buf.Reset()
+
+	q.WriteSQL(buf)
+
+	sql := sqlString(buf)
+	pool.Put(buf)
+	return sql
+}
+
+// writeColumnDefinition 按 SHOW CREATE TABLE 的顺序输出列定义
+func writeColumnDefinition(buf *bytes.Buffer, column *ColumnSchema) {
+	buf.WriteByte('`')
+	buf.WriteString(column.Name)
+	buf.WriteString("` ")
+	buf.WriteString(column.Type)
+	if column.Charset != "" {
+		buf.WriteString(" CHARACTER SET ")
+		buf.WriteString(column.Charset)
+	}
+	if column.Collate != "" {
+		buf.WriteString(" COLLATE ")
+		buf.WriteString(column.Collate)
+	}
+	if !column.Nullable {
+		buf.WriteString(" NOT NULL")
+	}
+	if column.Default != nil {
+		buf.WriteString(" DEFAULT ")
+		buf.WriteString(*column.Default)
+	}
+	if column.AutoIncrement {
+		buf.WriteString(" AUTO_INCREMENT")
+	}
+	if column.OnUpdate != "" {
+		buf.WriteString(" ON UPDATE ")
+		buf.WriteString(column.OnUpdate)
+	}
+	if column.Comment != "" {
+		buf.WriteString(" COMMENT ")
+		writeStringLiteral(buf, column.Comment)
+	}
+}
+
+func writeIndexDefinition(buf *bytes.Buffer, index *IndexSchema) {
+	switch index.Kind {
+	case PrimaryKey:
+		buf.WriteString("PRIMARY KEY (")
+	default:
+		switch index.Kind {
+		case UniqueIndex:
+			buf.WriteString("UNIQUE KEY `")
+		case FulltextIndex:
+			buf.WriteString("FULLTEXT KEY `")
+		case SpatialIndex:
+			buf.WriteString("SPATIAL KEY `")
+		default:
+			buf.WriteString("KEY `")
+		}
+		buf.WriteString(index.Name)
+		buf.WriteString("` (")
+	}
+	writeColumnNames(buf, index.Columns)
+	buf.WriteByte(')')
+}
+
+func writeColumnNames(buf *bytes.Buffer, names []string) {
+	for i, name := range names {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteByte('`')
+		buf.WriteString(name)
+		buf.WriteByte('`')
+	}
+}
+
+var stringLiteralReplacer = strings.NewReplacer(`\`, `\\`, `'`, `''`)
+
+func writeStringLiteral(buf *bytes.Buffer, s string) {
+	buf.WriteByte('\'')
+	stringLiteralReplacer.WriteString(buf, s)
+	buf.WriteByte('\'')
+}
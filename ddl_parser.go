@@ -0,0 +1,528 @@
+package sb
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenKind uint8
+
+const (
+	identToken  tokenKind = iota // 关键字或未转义的标识符
+	quotedToken                  // `name`
+	stringToken                  // 'str' 或 "str"
+	numberToken
+	symbolToken
+)
+
+type token struct {
+	kind tokenKind
+	text string // stringToken 和 quotedToken 为去掉引号和转义后的内容
+	raw  string // 原始内容
+}
+
+func (t token) is(keyword string) bool {
+	return t.kind == identToken && strings.EqualFold(t.text, keyword)
+}
+
+func (t token) isSymbol(symbol string) bool {
+	return t.kind == symbolToken && t.text == symbol
+}
+
+// tokenize 将 SQL 切分为 token，会忽略注释（包括 mysqldump 输出的 /*!40101 ... */）
+func tokenize(sql string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(sql); {
+		c := sql[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '#' || (c == '-' && strings.HasPrefix(sql[i:], "-- ")):
+			for i < len(sql) && sql[i] != '\n' {
+				i++
+			}
+		case c == '/' && strings.HasPrefix(sql[i:], "/*"):
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("sb: unterminated comment at offset %d", i)
+			}
+			i += end + 4
+		case c == '\'' || c == '"' || c == '`':
+			start := i
+			var text strings.Builder
+			i++
+			for {
+				if i >= len(sql) {
+					return nil, fmt.Errorf("sb: unterminated quote at offset %d", start)
+				}
+				if sql[i] == c {
+					if i+1 < len(sql) && sql[i+1] == c { // '' 表示 '
+						text.WriteByte(c)
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				if sql[i] == '\\' && c != '`' && i+1 < len(sql) {
+					text.WriteByte(unescape(sql[i+1]))
+					i += 2
+					continue
+				}
+				text.WriteByte(sql[i])
+				i++
+			}
+			kind := stringToken
+			if c == '`' {
+				kind = quotedToken
+			}
+			tokens = append(tokens, token{kind: kind, text: text.String(), raw: sql[start:i]})
+		case isIdentByte(c):
+			start := i
+			for i < len(sql) && (isIdentByte(sql[i]) || (sql[i] == '.' && isNumber(sql[start:i]))) { // 1.5 是一个数字
+				i++
+			}
+			kind := identToken
+			if c >= '0' && c <= '9' && isNumber(sql[start:i]) {
+				kind = numberToken
+			}
+			tokens = append(tokens, token{kind: kind, text: sql[start:i], raw: sql[start:i]})
+		default:
+			tokens = append(tokens, token{kind: symbolToken, text: sql[i : i+1], raw: sql[i : i+1]})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '$' || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
+
+func isNumber(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if (s[i] < '0' || s[i] > '9') && s[i] != '.' {
+			return false
+		}
+	}
+	return true
+}
+
+func unescape(c byte) byte {
+	switch c {
+	case 'n':
+		return '\n'
+	case 't':
+		return '\t'
+	case 'r':
+		return '\r'
+	case '0':
+		return 0
+	}
+	return c
+}
+
+type ddlParser struct {
+	tokens []token
+	pos    int
+}
+
+// ParseDDL 解析 SQL 中所有的 CREATE TABLE 语句，其他语句会被忽略，
+// 可以直接解析 mysqldump --no-data 的输出
+func ParseDDL(sql string) ([]*TableSchema, error) {
+	tokens, err := tokenize(sql)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &ddlParser{tokens: tokens}
+	var tables []*TableSchema
+	for !p.eof() {
+		if p.peek().is("CREATE") {
+			start := p.pos
+			p.next()
+			p.accept("TEMPORARY")
+			if p.accept("TABLE") {
+				table, err := p.parseCreateTable()
+				if err != nil {
+					return nil, err
+				}
+				tables = append(tables, table)
+				continue
+			}
+			p.pos = start
+		}
+		p.skipStatement()
+	}
+	return tables, nil
+}
+
+func (p *ddlParser) eof() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *ddlParser) peek() token {
+	if p.eof() {
+		return token{kind: symbolToken}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *ddlParser) next() token {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *ddlParser) accept(keyword string) bool {
+	if p.peek().is(keyword) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *ddlParser) acceptSymbol(symbol string) bool {
+	if p.peek().isSymbol(symbol) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *ddlParser) expectSymbol(symbol string) error {
+	if !p.acceptSymbol(symbol) {
+		return p.errorf("expected %q", symbol)
+	}
+	return nil
+}
+
+func (p *ddlParser) errorf(format string, args ...any) error {
+	near := "EOF"
+	if !p.eof() {
+		near = p.tokens[p.pos].raw
+	}
+	return fmt.Errorf("sb: parse DDL: %s near %q", fmt.Sprintf(format, args...), near)
+}
+
+func (p *ddlParser) skipStatement() {
+	for !p.eof() {
+		if p.next().isSymbol(";") {
+			return
+		}
+	}
+}
+
+// skipElement 跳过当前括号层级内直到 , 或 ) 的内容
+func (p *ddlParser) skipElement() {
+	depth := 0
+	for !p.eof() {
+		t := p.peek()
+		if depth == 0 && (t.isSymbol(",") || t.isSymbol(")")) {
+			return
+		}
+		if t.isSymbol("(") {
+			depth++
+		} else if t.isSymbol(")") {
+			depth--
+		}
+		p.pos++
+	}
+}
+
+func (p *ddlParser) parseIdent() (string, error) {
+	t := p.next()
+	if t.kind != identToken && t.kind != quotedToken {
+		p.pos--
+		return "", p.errorf("expected identifier")
+	}
+	return t.text, nil
+}
+
+// parseTableName 解析 name 或 schema.name，只返回表名
+func (p *ddlParser) parseTableName() (string, error) {
+	name, err := p.parseIdent()
+	if err != nil {
+		return "", err
+	}
+	if p.acceptSymbol(".") {
+		return p.parseIdent()
+	}
+	return name, nil
+}
+
+func (p *ddlParser) parseCreateTable() (*TableSchema, error) {
+	if p.accept("IF") {
+		if !p.accept("NOT") || !p.accept("EXISTS") {
+			return nil, p.errorf("expected IF NOT EXISTS")
+		}
+	}
+	name, err := p.parseTableName()
+	if err != nil {
+		return nil, err
+	}
+	table := &TableSchema{Name: name}
+	if p.accept("LIKE") { // CREATE TABLE a LIKE b 无法得到表结构
+		return nil, p.errorf("CREATE TABLE ... LIKE is not supported")
+	}
+	if err = p.expectSymbol("("); err != nil {
+		return nil, err
+	}
+
+	for {
+		if err = p.parseTableElement(table); err != nil {
+			return nil, err
+		}
+		if p.acceptSymbol(",") {
+			continue
+		}
+		if err = p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+		break
+	}
+
+	p.parseTableOptions(table)
+	p.skipStatement() // PARTITION BY 等
+	return table, nil
+}
+
+func (p *ddlParser) parseTableElement(table *TableSchema) error {
+	t := p.peek()
+	if t.kind == quotedToken || (t.kind == identToken && !isIndexKeyword(t)) {
+		return p.parseColumn(table)
+	}
+
+	p.accept("CONSTRAINT")
+	if t = p.peek(); t.kind == quotedToken || (t.kind == identToken && !isIndexKeyword(t)) {
+		p.next() // 约束名
+	}
+
+	index := &IndexSchema{}
+	switch {
+	case p.accept("PRIMARY"):
+		p.accept("KEY")
+		index.Kind = PrimaryKey
+	case p.accept("UNIQUE"):
+		index.Kind = UniqueIndex
+	case p.accept("FULLTEXT"):
+		index.Kind = FulltextIndex
+	case p.accept("SPATIAL"):
+		index.Kind = SpatialIndex
+	case p.accept("KEY") || p.accept("INDEX"):
+	default: // FOREIGN KEY、CHECK 等
+		p.skipElement()
+		return nil
+	}
+	if index.Kind != PrimaryKey && index.Kind != NormalIndex {
+		_ = p.accept("KEY") || p.accept("INDEX")
+	}
+
+	if index.Kind != PrimaryKey && !p.peek().isSymbol("(") && !p.peek().is("USING") {
+		name, err := p.parseIdent()
+		if err != nil {
+			return err
+		}
+		index.Name = name
+	}
+	if p.accept("USING") {
+		p.next()
+	}
+
+	if err := p.expectSymbol("("); err != nil {
+		return err
+	}
+	for {
+		if p.peek().isSymbol("(") { // 函数索引
+			p.skipElement()
+		} else {
+			name, err := p.parseIdent()
+			if err != nil {
+				return err
+			}
+			index.Columns = append(index.Columns, name)
+			p.skipElement() // 前缀长度、ASC/DESC
+		}
+		if p.acceptSymbol(",") {
+			continue
+		}
+		if err := p.expectSymbol(")"); err != nil {
+			return err
+		}
+		break
+	}
+	p.skipElement() // COMMENT、INVISIBLE 等
+	table.Indexes = append(table.Indexes, index)
+	return nil
+}
+
+func isIndexKeyword(t token) bool {
+	for _, keyword := range [...]string{"PRIMARY", "UNIQUE", "KEY", "INDEX", "FULLTEXT", "SPATIAL", "CONSTRAINT", "FOREIGN", "CHECK"} {
+		if t.is(keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// columnAttributes 是列定义中类型之后可能出现的关键字
+var columnAttributes = [...]string{
+	"NOT", "NULL", "DEFAULT", "AUTO_INCREMENT", "COMMENT", "COLLATE", "CHARACTER", "CHARSET", "PRIMARY", "UNIQUE",
+	"KEY", "ON", "GENERATED", "AS", "VISIBLE", "INVISIBLE", "COLUMN_FORMAT", "STORAGE", "REFERENCES", "CHECK", "CONSTRAINT", "SRID",
+}
+
+func isColumnAttribute(t token) bool {
+	for _, keyword := range columnAttributes {
+		if t.is(keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *ddlParser) parseColumn(table *TableSchema) error {
+	name, err := p.parseIdent()
+	if err != nil {
+		return err
+	}
+	column := &ColumnSchema{Name: name, Nullable: true}
+
+	var typ strings.Builder
+	for !p.eof() {
+		t := p.peek()
+		if t.isSymbol(",") || t.isSymbol(")") || isColumnAttribute(t) {
+			break
+		}
+		if t.isSymbol("(") {
+			typ.WriteString(p.parseParenthesized())
+			continue
+		}
+		if typ.Len() > 0 {
+			typ.WriteByte(' ')
+		}
+		typ.WriteString(strings.ToLower(t.raw))
+		p.pos++
+	}
+	if typ.Len() == 0 {
+		return p.errorf("expected column type")
+	}
+	column.Type = typ.String()
+
+	for !p.eof() {
+		t := p.peek()
+		if t.isSymbol(",") || t.isSymbol(")") {
+			break
+		}
+		switch {
+		case p.accept("NOT"):
+			p.accept("NULL")
+			column.Nullable = false
+		case p.accept("NULL"):
+			column.Nullable = true
+		case p.accept("DEFAULT"):
+			value := p.parseExpression()
+			column.Default = &value
+		case p.accept("AUTO_INCREMENT"):
+			column.AutoIncrement = true
+		case p.accept("ON"):
+			p.accept("UPDATE")
+			column.OnUpdate = p.parseExpression()
+		case p.accept("COMMENT"):
+			column.Comment = p.next().text
+		case p.accept("COLLATE"):
+			column.Collate = p.next().text
+		case p.accept("CHARACTER"):
+			p.accept("SET")
+			column.Charset = p.next().text
+		case p.accept("CHARSET"):
+			column.Charset = p.next().text
+		case p.accept("PRIMARY"):
+			p.accept("KEY")
+			column.Nullable = false
+			table.Indexes = append(table.Indexes, &IndexSchema{Kind: PrimaryKey, Columns: []string{name}})
+		case p.accept("UNIQUE"):
+			p.accept("KEY")
+			table.Indexes = append(table.Indexes, &IndexSchema{Name: name, Kind: UniqueIndex, Columns: []string{name}})
+		case t.isSymbol("("):
+			p.parseParenthesized() // GENERATED ALWAYS AS (...) 等
+		default:
+			p.pos++
+		}
+	}
+	table.Columns = append(table.Columns, column)
+	return nil
+}
+
+// parseParenthesized 返回括号内的原始内容（包括括号），逗号后不加空格
+func (p *ddlParser) parseParenthesized() string {
+	var b strings.Builder
+	depth := 0
+	prevWord := false
+	for !p.eof() {
+		t := p.next()
+		isWord := t.kind != symbolToken
+		if isWord && prevWord {
+			b.WriteByte(' ')
+		}
+		b.WriteString(t.raw)
+		prevWord = isWord
+		if t.isSymbol("(") {
+			depth++
+		} else if t.isSymbol(")") {
+			depth--
+			if depth == 0 {
+				break
+			}
+		}
+	}
+	return b.String()
+}
+
+// parseExpression 解析 DEFAULT 和 ON UPDATE 之后的表达式
+func (p *ddlParser) parseExpression() string {
+	if p.peek().isSymbol("(") {
+		return p.parseParenthesized()
+	}
+	t := p.next()
+	if t.isSymbol("-") || t.isSymbol("+") { // 负数
+		return t.raw + p.next().raw
+	}
+	if t.kind == identToken && p.peek().isSymbol("(") { // CURRENT_TIMESTAMP(3)、b'0' 等
+		return strings.ToUpper(t.raw) + p.parseParenthesized()
+	}
+	if t.kind == identToken && p.peek().kind == stringToken { // b'0'、_utf8mb4'a' 等
+		return t.raw + p.next().raw
+	}
+	if t.kind == identToken {
+		return strings.ToUpper(t.raw)
+	}
+	return t.raw
+}
+
+func (p *ddlParser) parseTableOptions(table *TableSchema) {
+	for !p.eof() && !p.peek().isSymbol(";") {
+		switch {
+		case p.accept("ENGINE"):
+			p.acceptSymbol("=")
+			table.Engine = p.next().text
+		case p.accept("DEFAULT"):
+		case p.accept("CHARSET"):
+			p.acceptSymbol("=")
+			table.Charset = p.next().text
+		case p.accept("CHARACTER"):
+			p.accept("SET")
+			p.acceptSymbol("=")
+			table.Charset = p.next().text
+		case p.accept("COLLATE"):
+			p.acceptSymbol("=")
+			table.Collate = p.next().text
+		case p.accept("COMMENT"):
+			p.acceptSymbol("=")
+			table.Comment = p.next().text
+		case p.peek().is("PARTITION"):
+			return
+		default:
+			p.pos++
+		}
+	}
+}
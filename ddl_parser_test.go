@@ -0,0 +1,122 @@
+package sb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestParseDDL(t *testing.T) {
+	sql := "-- MySQL dump 10.13\n" +
+		"DROP TABLE IF EXISTS `user`;\n" +
+		"/*!40101 SET @saved_cs_client     = @@character_set_client */;\n" +
+		"/*!50503 SET character_set_client = utf8mb4 */;\n" +
+		"CREATE TABLE `user` (\n" +
+		"  `id` bigint unsigned NOT NULL AUTO_INCREMENT,\n" +
+		"  `name` varchar(64) COLLATE utf8mb4_bin NOT NULL DEFAULT '' COMMENT 'user''s \\'name\\'',\n" +
+		"  `score` decimal(10,2) DEFAULT '-1.50',\n" +
+		"  `status` enum('a','b') CHARACTER SET ascii NOT NULL DEFAULT 'a',\n" +
+		"  `flag` bit(1) NOT NULL DEFAULT b'0',\n" +
+		"  `dept_id` int DEFAULT NULL,\n" +
+		"  `created_at` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),\n" +
+		"  `updated_at` timestamp NULL DEFAULT current_timestamp ON UPDATE CURRENT_TIMESTAMP,\n" +
+		"  `uuid` char(36) GENERATED ALWAYS AS (uuid()) VIRTUAL,\n" +
+		"  PRIMARY KEY (`id`),\n" +
+		"  UNIQUE KEY `uk_name` (`name`(10),`dept_id` DESC) USING BTREE,\n" +
+		"  KEY `idx_dept` (`dept_id`) COMMENT 'dept',\n" +
+		"  FULLTEXT KEY `ft_name` (`name`),\n" +
+		"  CONSTRAINT `fk_dept` FOREIGN KEY (`dept_id`) REFERENCES `dept` (`id`) ON DELETE CASCADE,\n" +
+		"  CONSTRAINT `chk_score` CHECK ((`score` > 0))\n" +
+		") ENGINE=InnoDB AUTO_INCREMENT=10 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='users';\n" +
+		"/*!40101 SET character_set_client = @saved_cs_client */;\n" +
+		"create table if not exists db.dept (id int primary key, name varchar(10) unique, index (name)) partition by hash(id) partitions 4;\n"
+
+	tables, err := ParseDDL(sql)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []*TableSchema{
+		{
+			Name: "user",
+			Columns: []*ColumnSchema{
+				{Name: "id", Type: "bigint unsigned", AutoIncrement: true},
+				{Name: "name", Type: "varchar(64)", Default: strPtr("''"), Collate: "utf8mb4_bin", Comment: "user's 'name'"},
+				{Name: "score", Type: "decimal(10,2)", Nullable: true, Default: strPtr("'-1.50'")},
+				{Name: "status", Type: "enum('a','b')", Default: strPtr("'a'"), Charset: "ascii"},
+				{Name: "flag", Type: "bit(1)", Default: strPtr("b'0'")},
+				{Name: "dept_id", Type: "int", Nullable: true, Default: strPtr("NULL")},
+				{Name: "created_at", Type: "datetime(3)", Default: strPtr("CURRENT_TIMESTAMP(3)")},
+				{Name: "updated_at", Type: "timestamp", Nullable: true, Default: strPtr("CURRENT_TIMESTAMP"), OnUpdate: "CURRENT_TIMESTAMP"},
+				{Name: "uuid", Type: "char(36)", Nullable: true},
+			},
+			Indexes: []*IndexSchema{
+				{Kind: PrimaryKey, Columns: []string{"id"}},
+				{Name: "uk_name", Kind: UniqueIndex, Columns: []string{"name", "dept_id"}},
+				{Name: "idx_dept", Kind: NormalIndex, Columns: []string{"dept_id"}},
+				{Name: "ft_name", Kind: FulltextIndex, Columns: []string{"name"}},
+			},
+			Engine:  "InnoDB",
+			Charset: "utf8mb4",
+			Collate: "utf8mb4_0900_ai_ci",
+			Comment: "users",
+		},
+		{
+			Name: "dept",
+			Columns: []*ColumnSchema{
+				{Name: "id", Type: "int"},
+				{Name: "name", Type: "varchar(10)", Nullable: true},
+			},
+			Indexes: []*IndexSchema{
+				{Kind: PrimaryKey, Columns: []string{"id"}},
+				{Name: "name", Kind: UniqueIndex, Columns: []string{"name"}},
+				{Kind: NormalIndex, Columns: []string{"name"}},
+			},
+		},
+	}
+
+	if len(tables) != len(expected) {
+		t.Fatalf("got %d tables, want %d", len(tables), len(expected))
+	}
+	for i, table := range tables {
+		want := expected[i]
+		if len(table.Columns) != len(want.Columns) {
+			t.Fatalf("got %d columns, want %d", len(table.Columns), len(want.Columns))
+		}
+		for j, column := range table.Columns {
+			if !reflect.DeepEqual(column, want.Columns[j]) {
+				t.Errorf("got %+v, want %+v", *column, *want.Columns[j])
+			}
+		}
+		if len(table.Indexes) != len(want.Indexes) {
+			t.Fatalf("got %d indexes, want %d", len(table.Indexes), len(want.Indexes))
+		}
+		for j, index := range table.Indexes {
+			if !reflect.DeepEqual(index, want.Indexes[j]) {
+				t.Errorf("got %+v, want %+v", *index, *want.Indexes[j])
+			}
+		}
+		table.Columns, table.Indexes, want.Columns, want.Indexes = nil, nil, nil, nil
+		if !reflect.DeepEqual(table, want) {
+			t.Errorf("got %+v, want %+v", *table, *want)
+		}
+	}
+}
+
+func TestParseDDLError(t *testing.T) {
+	for _, sql := range []string{
+		"CREATE TABLE `a` (",
+		"CREATE TABLE `a` (`id`)",
+		"CREATE TABLE `a` LIKE `b`",
+		"CREATE TABLE `a` (`id` int",
+		"CREATE TABLE `a` (`id` int) COMMENT 'a",
+		"/* a",
+	} {
+		if _, err := ParseDDL(sql); err == nil {
+			t.Errorf("%s: error not reported", sql)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package sb
+
+import (
+	"strings"
+	"testing"
+)
+
+type AccountTable struct {
+	Table     `db:"account" ddl:"engine:InnoDB;charset:utf8mb4;collate:utf8mb4_bin;comment:账号"`
+	ID        Column `db:"id" ddl:"type:bigint unsigned;primary key;auto_increment"`
+	TenantID  Column `db:"tenant_id" ddl:"type:int;not null;unique:uk_name;index:idx_tenant"`
+	Name      Column `db:"name" ddl:"type:varchar(64);charset:utf8mb4;collate:utf8mb4_bin;not null;default:'';unique:uk_name;comment:it's a name"`
+	Bio       Column `ddl:"type:text;fulltext"`
+	UpdatedAt Column `db:"updated_at" ddl:"type:datetime;default:CURRENT_TIMESTAMP;on update:CURRENT_TIMESTAMP"`
+	Ignored   string
+	ignored   Column
+}
+
+type MembershipTable struct {
+	Table  `db:"membership"`
+	UserID Column `db:"user_id" ddl:"type:int;primary key"`
+	DeptID Column `db:"dept_id" ddl:"type:int;primary key;index"`
+}
+
+type InvalidTable struct {
+	Table `db:"invalid"`
+	ID    Column `db:"id"`
+}
+
+type UnknownOptionTable struct {
+	Table `db:"unknown"`
+	ID    Column `db:"id" ddl:"type:int;primary"`
+}
+
+func TestCreateTable(t *testing.T) {
+	tests := []struct {
+		query    *CreateTableQuery
+		expected string
+	}{
+		{
+			query: CreateTable[AccountTable](),
+			expected: "CREATE TABLE `account` (" +
+				"`id` bigint unsigned NOT NULL AUTO_INCREMENT, " +
+				"`tenant_id` int NOT NULL, " +
+				"`name` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin NOT NULL DEFAULT '' COMMENT 'it''s a name', " +
+				"`bio` text, " +
+				"`updated_at` datetime DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP, " +
+				"PRIMARY KEY (`id`), UNIQUE KEY `uk_name` (`tenant_id`, `name`), KEY `idx_tenant` (`tenant_id`), FULLTEXT KEY `bio` (`bio`)" +
+				") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin COMMENT='账号'",
+		},
+		{
+			query:    CreateTable[MembershipTable]().IfNotExists(),
+			expected: "CREATE TABLE IF NOT EXISTS `membership` (`user_id` int NOT NULL, `dept_id` int NOT NULL, PRIMARY KEY (`user_id`, `dept_id`), KEY `dept_id` (`dept_id`))",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			if got := test.query.String(); got != test.expected {
+				t.Errorf("got %s, want %s", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestSchemaOfError(t *testing.T) {
+	if _, err := SchemaOf[InvalidTable](); err == nil || !strings.Contains(err.Error(), "no type") {
+		t.Errorf("got %v", err)
+	}
+	if _, err := SchemaOf[UnknownOptionTable](); err == nil || !strings.Contains(err.Error(), "unknown ddl option") {
+		t.Errorf("got %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("CreateTable not panic")
+		}
+	}()
+	CreateTable[InvalidTable]()
+}
+
+func TestCreateTableFromParsedDDL(t *testing.T) {
+	expected := CreateTable[AccountTable]().String()
+	tables, err := ParseDDL(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := CreateTableFromSchema(tables[0]).String(); got != expected {
+		t.Errorf("got %s, want %s", got, expected)
+	}
+}
+
+func TestDropTable(t *testing.T) {
+	a := New[AccountTable]("a")
+	m := New[MembershipTable]("")
+
+	tests := []struct {
+		query    *DropTableQuery
+		expected string
+	}{
+		{
+			query:    DropTable(a),
+			expected: "DROP TABLE `account`",
+		},
+		{
+			query:    DropTable(a, m).IfExists(),
+			expected: "DROP TABLE IF EXISTS `account`, `membership`",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			if got := test.query.String(); got != test.expected {
+				t.Errorf("got %s, want %s", got, test.expected)
+			}
+		})
+	}
+}
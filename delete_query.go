@@ -6,10 +6,15 @@ import (
 )
 
 type DeleteQuery struct {
-	table    AnyTable
-	where    Cond
-	orderBys OrderBys
-	limit    uint64
+	table        AnyTable
+	where        Cond
+	orderBys     OrderBys
+	limit        uint64
+	hard         bool
+	tenant       *Value // 由 Tenant 设置的租户
+	interceptors []Interceptor
+	comments     []string
+	hints        []string
 }
 
 func Delete(table AnyTable) *DeleteQuery {
@@ -40,12 +45,26 @@ func (q *DeleteQuery) Limit(limit uint64) *DeleteQuery {
 	return q
 }
 
+// WriteSQL 对声明了软删除的表输出 UPDATE `t` SET `deleted_at`=NOW() WHERE ... AND `deleted_at` IS NULL，
+// 调用 HardDelete 后输出 DELETE
 func (q *DeleteQuery) WriteSQL(buf *bytes.Buffer) {
-	buf.WriteString("DELETE `")
-	buf.WriteString(q.table.getName())
-	buf.WriteByte('`')
-	if q.where != nil {
-		q.where.WriteSQL(buf, NoAlias)
+	q = q.scoped()
+	where := andTenant(q.where, q.table, q.tenant)
+	writeComments(buf, q.comments)
+	if column := softDeleteColumn(q.table); column != nil && !q.hard {
+		buf.WriteString("UPDATE ")
+		writeHints(buf, q.hints)
+		writeTableName(buf, q.table)
+		buf.WriteString(" SET ")
+		column.Assign(Expr("NOW()")).WriteSQL(buf, NoAlias)
+		where = andScope(where, q.table, NotDeleted)
+	} else {
+		buf.WriteString("DELETE ")
+		writeHints(buf, q.hints)
+		writeTableName(buf, q.table)
+	}
+	if where != nil {
+		where.WriteSQL(buf, NoAlias)
 	}
 	q.orderBys.WriteSQL(buf, NoAlias)
 	if q.limit > 0 {
@@ -54,13 +73,54 @@ func (q *DeleteQuery) WriteSQL(buf *bytes.Buffer) {
 	}
 }
 
+// String 返回经过 Interceptor 处理的 SQL，StringE 返回错误时返回空字符串，主要用于调试和日志
 func (q *DeleteQuery) String() string {
+	sql, _ := q.StringE()
+	return sql
+}
+
+// StringE 返回经过 Interceptor 处理的 SQL，Interceptor 拒绝或严格模式下没有设置租户时返回错误
+func (q *DeleteQuery) StringE() (string, error) {
+	if err := q.checkTenant(); err != nil {
+		return "", err
+	}
+	return q.interception().interceptSQL(q.render())
+}
+
+func (q *DeleteQuery) render() string {
 	buf := pool.Get().(*bytes.Buffer)
 	buf.Reset()
 
 	q.WriteSQL(buf)
 
-	sql := buf.String()
+	sql := sqlString(buf)
 	pool.Put(buf)
 	return sql
 }
+
+// Build 返回 SQL 和参数，args 会按顺序填充未绑定值的 ?，sql.NamedArg 会按名字绑定 Param
+func (q *DeleteQuery) Build(args ...any) (Statement, error) {
+	if err := q.checkTenant(); err != nil {
+		return Statement{}, err
+	}
+	slots := q.appendArgs(nil)
+	bound, err := bindArgs(slots[:0], slots, args)
+	if err != nil {
+		return Statement{}, err
+	}
+	sql, bound, err := q.interception().intercept(q.render(), bound)
+	if err != nil {
+		return Statement{}, err
+	}
+	return Statement{SQL: sql, Args: bound}, nil
+}
+
+// Compile 生成 SQL 并返回可以反复绑定参数的 Compiled，之后对 q 的修改不会影响它
+func (q *DeleteQuery) Compile() *Compiled {
+	return newCompiled(q.render(), q.appendArgs(nil), q.checkTenant(), q.interception())
+}
+
+func (q *DeleteQuery) appendArgs(args []any) []any {
+	q = q.scoped()
+	return appendArgs(args, andTenant(q.where, q.table, q.tenant))
+}
@@ -25,12 +25,23 @@ func (e Expressions) WriteSQL(buf *bytes.Buffer, aliasMode AliasMode) {
 	}
 }
 
+func (e Expressions) appendArgs(args []any) []any {
+	for _, expression := range e {
+		args = appendArgs(args, expression)
+	}
+	return args
+}
+
 type Expr string
 
 func (e Expr) WriteSQL(buf *bytes.Buffer, aliasMode AliasMode) {
 	buf.WriteString(string(e))
 }
 
+func (e Expr) appendArgs(args []any) []any {
+	return appendPlaceholders(args, countMarks(string(e)))
+}
+
 const PH = Expr("?") // Placeholder 缩写
 
 type ConcatExpressions Expressions // 直接输出每个元素
@@ -45,6 +56,10 @@ func (e ConcatExpressions) WriteSQL(buf *bytes.Buffer, aliasMode AliasMode) {
 	}
 }
 
+func (e ConcatExpressions) appendArgs(args []any) []any {
+	return Expressions(e).appendArgs(args)
+}
+
 type Function struct {
 	Name        string
 	Expressions Expressions
@@ -70,7 +85,23 @@ func (f *Function) WriteSQL(buf *bytes.Buffer, aliasMode AliasMode) {
 	}
 }
 
+func (f *Function) appendArgs(args []any) []any {
+	return f.Expressions.appendArgs(args)
+}
+
 func (f *Function) As(alias string) *Function {
 	f.Alias = alias
 	return f
 }
+
+type Tuple Expressions // 输出为 (a, b, ...)，用于行比较
+
+func (t Tuple) WriteSQL(buf *bytes.Buffer, aliasMode AliasMode) {
+	buf.WriteByte('(')
+	Expressions(t).WriteSQL(buf, aliasMode)
+	buf.WriteByte(')')
+}
+
+func (t Tuple) appendArgs(args []any) []any {
+	return Expressions(t).appendArgs(args)
+}
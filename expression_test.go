@@ -92,3 +92,37 @@ func TestConcatExpressionsWriteSQL(t *testing.T) {
 		})
 	}
 }
+
+func TestTupleWriteSQL(t *testing.T) {
+	buf := bytes.NewBuffer(make([]byte, 0, bufferSize))
+
+	tests := []struct {
+		tuple     Tuple
+		aliasMode AliasMode
+		expected  string
+	}{
+		{
+			expected: "()",
+		},
+		{
+			tuple:     Tuple{PH, PH},
+			aliasMode: NoAlias,
+			expected:  "(?, ?)",
+		},
+		{
+			tuple:     Tuple{Column{name: "col1"}, Column{name: "col2", table: &Table{name: "test"}}},
+			aliasMode: UseAlias,
+			expected:  "(`col1`, `test`.`col2`)",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			buf.Reset()
+			test.tuple.WriteSQL(buf, test.aliasMode)
+			if got := buf.String(); got != test.expected {
+				t.Errorf("got %s, want %s", got, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package sb
+
+import "bytes"
+
+// IndexHint 是表的索引提示，如 USE INDEX (`idx_a`)
+type IndexHint struct {
+	kind    string // USE、FORCE、IGNORE
+	scope   string // JOIN、ORDER BY、GROUP BY，为空时不限定
+	indexes []string
+}
+
+// UseIndex 输出为 USE INDEX (`idx`, ...)，indexes 为空时表示不使用索引
+func UseIndex(indexes ...string) IndexHint {
+	return IndexHint{kind: "USE", indexes: indexes}
+}
+
+// ForceIndex 输出为 FORCE INDEX (`idx`, ...)
+func ForceIndex(indexes ...string) IndexHint {
+	return IndexHint{kind: "FORCE", indexes: indexes}
+}
+
+// IgnoreIndex 输出为 IGNORE INDEX (`idx`, ...)
+func IgnoreIndex(indexes ...string) IndexHint {
+	return IndexHint{kind: "IGNORE", indexes: indexes}
+}
+
+// ForJoin 限定索引提示只用于查找行和 JOIN
+func (h IndexHint) ForJoin() IndexHint {
+	h.scope = "JOIN"
+	return h
+}
+
+// ForOrderBy 限定索引提示只用于 ORDER BY
+func (h IndexHint) ForOrderBy() IndexHint {
+	h.scope = "ORDER BY"
+	return h
+}
+
+// ForGroupBy 限定索引提示只用于 GROUP BY
+func (h IndexHint) ForGroupBy() IndexHint {
+	h.scope = "GROUP BY"
+	return h
+}
+
+func (h IndexHint) WriteSQL(buf *bytes.Buffer) {
+	buf.WriteString(h.kind)
+	buf.WriteString(" INDEX ")
+	if h.scope != "" {
+		buf.WriteString("FOR ")
+		buf.WriteString(h.scope)
+		buf.WriteByte(' ')
+	}
+	buf.WriteByte('(')
+	for i, index := range h.indexes {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteByte('`')
+		buf.WriteString(index)
+		buf.WriteByte('`')
+	}
+	buf.WriteByte(')')
+}
+
+// TableRef 是带有索引提示的表，可以用在 From、FromJoin 和各种 JOIN 中：
+//
+//	Select(u.Name).FromJoin(u.IndexHints(ForceIndex("idx_name")).InnerJoin(d.IndexHints(UseIndex("PRIMARY")), d.ID.Eq(&u.DeptID)))
+//	// SELECT `u`.`name` FROM `user` AS `u` FORCE INDEX (`idx_name`) JOIN `dept` AS `d` USE INDEX (`PRIMARY`) ON ...
+//
+// 用在 INSERT、UPDATE、DELETE 中时会忽略索引提示
+type TableRef struct {
+	AnyTable
+	hints []IndexHint
+}
+
+// IndexHints 返回带有索引提示的表，列仍使用原来的表对象
+func (t *Table) IndexHints(hints ...IndexHint) TableRef {
+	return TableRef{AnyTable: t, hints: hints}
+}
+
+func (t TableRef) InnerJoin(table AnyTable, on Cond) FromTables {
+	return FromTables{table: t}.InnerJoin(table, on)
+}
+
+func (t TableRef) LeftJoin(table AnyTable, on Cond) FromTables {
+	return FromTables{table: t}.LeftJoin(table, on)
+}
+
+func (t TableRef) RightJoin(table AnyTable, on Cond) FromTables {
+	return FromTables{table: t}.RightJoin(table, on)
+}
+
+func (t TableRef) StraightJoin(table AnyTable, on Cond) FromTables {
+	return FromTables{table: t}.StraightJoin(table, on)
+}
+
+func (t TableRef) CrossJoin(table AnyTable) FromTables {
+	return FromTables{table: t}.CrossJoin(table)
+}
+
+func (t TableRef) NaturalJoin(table AnyTable) FromTables {
+	return FromTables{table: t}.NaturalJoin(table)
+}
+
+func (t TableRef) InnerJoinUsing(table AnyTable, columns ...Column) FromTables {
+	return FromTables{table: t}.InnerJoinUsing(table, columns...)
+}
+
+func (t TableRef) LeftJoinUsing(table AnyTable, columns ...Column) FromTables {
+	return FromTables{table: t}.LeftJoinUsing(table, columns...)
+}
+
+func (t TableRef) RightJoinUsing(table AnyTable, columns ...Column) FromTables {
+	return FromTables{table: t}.RightJoinUsing(table, columns...)
+}
+
+// writeIndexHints 在表名和别名之后输出索引提示
+func writeIndexHints(buf *bytes.Buffer, table AnyTable) {
+	ref, ok := table.(TableRef)
+	if !ok {
+		return
+	}
+	for _, hint := range ref.hints {
+		buf.WriteByte(' ')
+		hint.WriteSQL(buf)
+	}
+}
@@ -0,0 +1,55 @@
+package sb
+
+import "testing"
+
+func TestIndexHints(t *testing.T) {
+	u := New[UserTable]("u")
+	d := New[DeptTable]("d")
+	du := New[DeptUserTable]("du")
+	p := New[PostTable]("p")
+
+	tests := []struct {
+		query    *SelectQuery
+		expected string
+	}{
+		{
+			query:    u.Select().From(u.IndexHints(UseIndex("idx_name"))).Where(u.Name.Eq(PH)),
+			expected: "SELECT * FROM `user` USE INDEX (`idx_name`) WHERE `name` = ?",
+		},
+		{
+			query:    u.Select().From(u.IndexHints(UseIndex())),
+			expected: "SELECT * FROM `user` USE INDEX ()",
+		},
+		{
+			query:    u.Select().From(u.IndexHints(ForceIndex("idx_a", "idx_b").ForJoin(), IgnoreIndex("PRIMARY").ForOrderBy(), UseIndex("idx_c").ForGroupBy())),
+			expected: "SELECT * FROM `user` FORCE INDEX FOR JOIN (`idx_a`, `idx_b`) IGNORE INDEX FOR ORDER BY (`PRIMARY`) USE INDEX FOR GROUP BY (`idx_c`)",
+		},
+		{
+			query: Select(u.Name, d.Name).FromJoin(u.IndexHints(ForceIndex("PRIMARY")).
+				InnerJoin(du.IndexHints(UseIndex("idx_user")), du.UserID.Eq(&u.ID)).
+				LeftJoin(d, d.ID.Eq(&du.DeptID))),
+			expected: "SELECT `u`.`name`, `d`.`name` FROM `user` AS `u` FORCE INDEX (`PRIMARY`) JOIN `dept_user` AS `du` USE INDEX (`idx_user`) ON `du`.`userid` = `u`.`id` LEFT JOIN `dept` AS `d` ON `d`.`id` = `du`.`deptid`",
+		},
+		{
+			query:    Select(u.Name).FromJoin(u.LeftJoin(d.IndexHints(IgnoreIndex("idx_name")), d.ID.Eq(&u.ID))),
+			expected: "SELECT `u`.`name` FROM `user` AS `u` LEFT JOIN `dept` AS `d` IGNORE INDEX (`idx_name`) ON `d`.`id` = `u`.`id`",
+		},
+		{
+			query:    Select(u.Name).FromJoin(u.IndexHints(UseIndex("idx_name")).RightJoin(d, d.ID.Eq(&u.ID))),
+			expected: "SELECT `u`.`name` FROM `user` AS `u` USE INDEX (`idx_name`) RIGHT JOIN `dept` AS `d` ON `d`.`id` = `u`.`id`",
+		},
+		{
+			// 软删除等依赖表元数据的功能不受影响
+			query:    Select(u.Name).FromJoin(u.InnerJoin(p.IndexHints(UseIndex("idx_author")), p.AuthorID.Eq(&u.ID))),
+			expected: "SELECT `u`.`name` FROM `user` AS `u` JOIN `post` AS `p` USE INDEX (`idx_author`) ON `p`.`author_id` = `u`.`id` AND `p`.`deleted_at` IS NULL",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			if got := test.query.String(); got != test.expected {
+				t.Errorf("got %s, want %s", got, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package sb
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+)
+
+// InformationSchema 是 information_schema 中 TABLES、COLUMNS 和 STATISTICS 表的快照，
+// 字段名与 information_schema 的列名相同，可以用类似以下的语句导出为 JSON：
+//
+//	SELECT JSON_OBJECT(
+//		'tables', (SELECT JSON_ARRAYAGG(JSON_OBJECT('TABLE_NAME', TABLE_NAME, 'ENGINE', ENGINE, ...)) FROM information_schema.TABLES WHERE TABLE_SCHEMA = ?),
+//		'columns', ...,
+//		'statistics', ...
+//	)
+type InformationSchema struct {
+	Tables []struct {
+		TableName      string `json:"TABLE_NAME"`
+		Engine         string `json:"ENGINE"`
+		TableCollation string `json:"TABLE_COLLATION"`
+		TableComment   string `json:"TABLE_COMMENT"`
+	} `json:"tables"`
+	Columns []struct {
+		TableName        string  `json:"TABLE_NAME"`
+		ColumnName       string  `json:"COLUMN_NAME"`
+		OrdinalPosition  int     `json:"ORDINAL_POSITION"`
+		ColumnDefault    *string `json:"COLUMN_DEFAULT"`
+		IsNullable       string  `json:"IS_NULLABLE"`
+		ColumnType       string  `json:"COLUMN_TYPE"`
+		CharacterSetName string  `json:"CHARACTER_SET_NAME"`
+		CollationName    string  `json:"COLLATION_NAME"`
+		Extra            string  `json:"EXTRA"`
+		ColumnComment    string  `json:"COLUMN_COMMENT"`
+	} `json:"columns"`
+	Statistics []struct {
+		TableName  string `json:"TABLE_NAME"`
+		IndexName  string `json:"INDEX_NAME"`
+		NonUnique  int    `json:"NON_UNIQUE"`
+		SeqInIndex int    `json:"SEQ_IN_INDEX"`
+		ColumnName string `json:"COLUMN_NAME"`
+		IndexType  string `json:"INDEX_TYPE"`
+	} `json:"statistics"`
+}
+
+// LoadInformationSchema 从 JSON 格式的 InformationSchema 中读取表结构，表按名字排序
+func LoadInformationSchema(r io.Reader) ([]*TableSchema, error) {
+	var snapshot InformationSchema
+	if err := json.NewDecoder(r).Decode(&snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot.Schemas(), nil
+}
+
+func (s *InformationSchema) Schemas() []*TableSchema {
+	tables := map[string]*TableSchema{}
+	getTable := func(name string) *TableSchema {
+		table, ok := tables[name]
+		if !ok {
+			table = &TableSchema{Name: name}
+			tables[name] = table
+		}
+		return table
+	}
+
+	for _, t := range s.Tables {
+		table := getTable(t.TableName)
+		table.Engine = t.Engine
+		table.Collate = t.TableCollation
+		if charset, _, ok := strings.Cut(t.TableCollation, "_"); ok {
+			table.Charset = charset
+		}
+		table.Comment = t.TableComment
+	}
+
+	columns := append(s.Columns[:0:0], s.Columns...) // 不修改调用方的切片
+	sort.SliceStable(columns, func(i, j int) bool { return columns[i].OrdinalPosition < columns[j].OrdinalPosition })
+	for _, c := range columns {
+		table := getTable(c.TableName)
+		column := &ColumnSchema{
+			Name:     c.ColumnName,
+			Type:     c.ColumnType,
+			Nullable: c.IsNullable == "YES",
+			Charset:  c.CharacterSetName,
+			Collate:  c.CollationName,
+			Comment:  c.ColumnComment,
+		}
+
+		extra := strings.ToLower(c.Extra)
+		column.AutoIncrement = strings.Contains(extra, "auto_increment")
+		if i := strings.Index(extra, "on update "); i >= 0 {
+			column.OnUpdate = strings.ToUpper(strings.Fields(c.Extra[i+len("on update "):])[0])
+		}
+		if c.ColumnDefault != nil {
+			value := *c.ColumnDefault
+			if !strings.Contains(extra, "default_generated") { // 非表达式的默认值是去掉引号的字面值
+				value = "'" + stringLiteralReplacer.Replace(value) + "'"
+			} else if !strings.HasPrefix(strings.ToUpper(value), "CURRENT_TIMESTAMP") {
+				value = "(" + value + ")"
+			}
+			column.Default = &value
+		}
+		table.Columns = append(table.Columns, column)
+	}
+
+	statistics := append(s.Statistics[:0:0], s.Statistics...)
+	sort.SliceStable(statistics, func(i, j int) bool { return statistics[i].SeqInIndex < statistics[j].SeqInIndex })
+	for _, stat := range statistics {
+		table := getTable(stat.TableName)
+		var index *IndexSchema
+		for _, i := range table.Indexes {
+			if i.Name == stat.IndexName || (i.Kind == PrimaryKey && stat.IndexName == "PRIMARY") {
+				index = i
+				break
+			}
+		}
+		if index == nil {
+			index = &IndexSchema{Name: stat.IndexName}
+			switch {
+			case stat.IndexName == "PRIMARY":
+				index.Name = ""
+				index.Kind = PrimaryKey
+			case strings.EqualFold(stat.IndexType, "FULLTEXT"):
+				index.Kind = FulltextIndex
+			case strings.EqualFold(stat.IndexType, "SPATIAL"):
+				index.Kind = SpatialIndex
+			case stat.NonUnique == 0:
+				index.Kind = UniqueIndex
+			}
+			table.Indexes = append(table.Indexes, index)
+		}
+		index.Columns = append(index.Columns, stat.ColumnName)
+	}
+
+	result := make([]*TableSchema, 0, len(tables))
+	for _, table := range tables {
+		sort.SliceStable(table.Indexes, func(i, j int) bool { // 主键在前
+			return table.Indexes[i].Kind == PrimaryKey && table.Indexes[j].Kind != PrimaryKey
+		})
+		result = append(result, table)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
@@ -0,0 +1,65 @@
+package sb
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadInformationSchema(t *testing.T) {
+	data := `{
+		"tables": [{"TABLE_NAME": "account", "ENGINE": "InnoDB", "TABLE_COLLATION": "utf8mb4_bin", "TABLE_COMMENT": "账号"}],
+		"columns": [
+			{"TABLE_NAME": "account", "COLUMN_NAME": "name", "ORDINAL_POSITION": 3, "COLUMN_DEFAULT": "", "IS_NULLABLE": "NO", "COLUMN_TYPE": "varchar(64)", "CHARACTER_SET_NAME": "utf8mb4", "COLLATION_NAME": "utf8mb4_bin", "EXTRA": "", "COLUMN_COMMENT": "it's a name"},
+			{"TABLE_NAME": "account", "COLUMN_NAME": "id", "ORDINAL_POSITION": 1, "COLUMN_DEFAULT": null, "IS_NULLABLE": "NO", "COLUMN_TYPE": "bigint unsigned", "EXTRA": "auto_increment"},
+			{"TABLE_NAME": "account", "COLUMN_NAME": "tenant_id", "ORDINAL_POSITION": 2, "COLUMN_DEFAULT": null, "IS_NULLABLE": "NO", "COLUMN_TYPE": "int", "EXTRA": ""},
+			{"TABLE_NAME": "account", "COLUMN_NAME": "bio", "ORDINAL_POSITION": 4, "COLUMN_DEFAULT": null, "IS_NULLABLE": "YES", "COLUMN_TYPE": "text", "CHARACTER_SET_NAME": "utf8mb4", "COLLATION_NAME": "utf8mb4_bin", "EXTRA": ""},
+			{"TABLE_NAME": "account", "COLUMN_NAME": "updated_at", "ORDINAL_POSITION": 5, "COLUMN_DEFAULT": "CURRENT_TIMESTAMP", "IS_NULLABLE": "YES", "COLUMN_TYPE": "datetime", "EXTRA": "DEFAULT_GENERATED on update CURRENT_TIMESTAMP"},
+			{"TABLE_NAME": "other", "COLUMN_NAME": "id", "ORDINAL_POSITION": 1, "COLUMN_DEFAULT": "0", "IS_NULLABLE": "NO", "COLUMN_TYPE": "int", "EXTRA": ""}
+		],
+		"statistics": [
+			{"TABLE_NAME": "account", "INDEX_NAME": "uk_name", "NON_UNIQUE": 0, "SEQ_IN_INDEX": 2, "COLUMN_NAME": "name", "INDEX_TYPE": "BTREE"},
+			{"TABLE_NAME": "account", "INDEX_NAME": "uk_name", "NON_UNIQUE": 0, "SEQ_IN_INDEX": 1, "COLUMN_NAME": "tenant_id", "INDEX_TYPE": "BTREE"},
+			{"TABLE_NAME": "account", "INDEX_NAME": "idx_tenant", "NON_UNIQUE": 1, "SEQ_IN_INDEX": 1, "COLUMN_NAME": "tenant_id", "INDEX_TYPE": "BTREE"},
+			{"TABLE_NAME": "account", "INDEX_NAME": "bio", "NON_UNIQUE": 1, "SEQ_IN_INDEX": 1, "COLUMN_NAME": "bio", "INDEX_TYPE": "FULLTEXT"},
+			{"TABLE_NAME": "account", "INDEX_NAME": "PRIMARY", "NON_UNIQUE": 0, "SEQ_IN_INDEX": 1, "COLUMN_NAME": "id", "INDEX_TYPE": "BTREE"}
+		]
+	}`
+
+	tables, err := LoadInformationSchema(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var snapshot InformationSchema
+	if err = json.Unmarshal([]byte(data), &snapshot); err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.Schemas(); snapshot.Columns[0].ColumnName != "name" || snapshot.Statistics[0].SeqInIndex != 2 {
+		t.Errorf("input sorted in place: %+v", snapshot)
+	}
+	if len(tables) != 2 || tables[0].Name != "account" || tables[1].Name != "other" {
+		t.Fatalf("got %v", tables)
+	}
+
+	target, err := SchemaOf[AccountTable]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m := DiffSchema(tables[0], target); len(m.Changes) != 0 {
+		t.Errorf("got %v", m.Statements())
+	}
+	if tables[0].Charset != "utf8mb4" || tables[0].Comment != "账号" {
+		t.Errorf("got %+v", tables[0])
+	}
+	if got := *tables[1].Columns[0].Default; got != "'0'" {
+		t.Errorf("got %s", got)
+	}
+	var names []string
+	for _, index := range tables[0].Indexes {
+		names = append(names, index.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"", "uk_name", "idx_tenant", "bio"}) {
+		t.Errorf("got %v", names)
+	}
+}
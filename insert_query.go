@@ -5,13 +5,18 @@ import (
 )
 
 type InsertQuery struct {
-	table       AnyTable
-	columns     Columns
-	values      Expressions
-	selectQuery *SelectQuery
-	assignments Assignments
-	aliasMode   AliasMode // of values
-	ignore      bool
+	table        AnyTable
+	columns      Columns
+	values       Expressions
+	rows         []Expressions // 多行 VALUES，由 FromStructs 设置，不为 nil 但为空时表示没有需要插入的行
+	selectQuery  *SelectQuery
+	assignments  Assignments
+	aliasMode    AliasMode // of values
+	ignore       bool
+	tenant       *Value // 由 Tenant 设置的租户
+	interceptors []Interceptor
+	comments     []string
+	hints        []string
 }
 
 func Insert(table AnyTable) *InsertQuery {
@@ -30,36 +35,57 @@ func (q *InsertQuery) Columns(columns ...Column) *InsertQuery {
 
 func (q *InsertQuery) Values(values ...Expression) *InsertQuery {
 	q.values = values
+	q.rows = nil
 	return q
 }
 
 func (q *InsertQuery) Select(table AnyTable, values ...Expression) *InsertQuery {
 	q.selectQuery = Select(values...).From(table)
+	q.rows = nil
 	return q
 }
 
 func (q *InsertQuery) NamedValues(values ...Expression) *InsertQuery {
 	q.values = values
+	q.rows = nil
 	q.aliasMode = ColonPrefix
 	return q
 }
 
+// named 判断是否输出 NamedValues 的 :name 形式的值
+func (q *InsertQuery) named() bool {
+	return q.aliasMode == ColonPrefix && q.rows == nil && q.selectQuery == nil
+}
+
 func (q *InsertQuery) OnDuplicateKeyUpdate(assignments ...Assignment) *InsertQuery {
 	q.assignments = assignments
 	return q
 }
 
 func (q *InsertQuery) WriteSQL(buf *bytes.Buffer) {
+	q = q.scoped()
+	writeComments(buf, q.comments)
+	buf.WriteString("INSERT ")
+	writeHints(buf, q.hints)
 	if q.ignore {
-		buf.WriteString("INSERT IGNORE INTO `")
-	} else {
-		buf.WriteString("INSERT INTO `")
+		buf.WriteString("IGNORE ")
 	}
-	buf.WriteString(q.table.getName())
-	buf.WriteString("` (")
+	buf.WriteString("INTO ")
+	writeTableName(buf, q.table)
+	buf.WriteString(" (")
 	q.columns.WriteSQL(buf, NoAlias)
 
-	if q.selectQuery == nil {
+	if q.rows != nil {
+		buf.WriteString(") VALUES ")
+		for i, row := range q.rows {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteByte('(')
+			row.WriteSQL(buf, NoAlias)
+			buf.WriteByte(')')
+		}
+	} else if q.selectQuery == nil {
 		buf.WriteString(") VALUES (")
 		if q.values == nil {
 			count := len(q.columns)
@@ -90,13 +116,73 @@ func (q *InsertQuery) WriteSQL(buf *bytes.Buffer) {
 	}
 }
 
+// String 返回经过 Interceptor 处理的 SQL，StringE 返回错误时返回空字符串，主要用于调试和日志
 func (q *InsertQuery) String() string {
+	sql, _ := q.StringE()
+	return sql
+}
+
+// StringE 返回经过 Interceptor 处理的 SQL，语句无效、Interceptor 拒绝或严格模式下没有设置租户时返回错误
+func (q *InsertQuery) StringE() (string, error) {
+	if err := q.validate(); err != nil {
+		return "", err
+	}
+	return q.interception().interceptSQL(q.render())
+}
+
+func (q *InsertQuery) render() string {
 	buf := pool.Get().(*bytes.Buffer)
 	buf.Reset()
 
 	q.WriteSQL(buf)
 
-	sql := buf.String()
+	sql := sqlString(buf)
 	pool.Put(buf)
 	return sql
 }
+
+// Build 返回 SQL 和参数，args 会按顺序填充未绑定值的 ?，sql.NamedArg 会按名字绑定 Param
+func (q *InsertQuery) Build(args ...any) (Statement, error) {
+	if err := q.validate(); err != nil {
+		return Statement{}, err
+	}
+	slots := q.appendArgs(nil)
+	bound, err := bindArgs(slots[:0], slots, args)
+	if err != nil {
+		return Statement{}, err
+	}
+	sql, bound, err := q.interception().intercept(q.render(), bound)
+	if err != nil {
+		return Statement{}, err
+	}
+	return Statement{SQL: sql, Args: bound}, nil
+}
+
+// Compile 生成 SQL 并返回可以反复绑定参数的 Compiled，之后对 q 的修改不会影响它
+func (q *InsertQuery) Compile() *Compiled {
+	return newCompiled(q.render(), q.appendArgs(nil), q.validate(), q.interception())
+}
+
+// validate 返回语句无法执行的原因，如 FromStructs 没有行、严格模式下没有设置租户
+func (q *InsertQuery) validate() error {
+	if q.rows != nil && len(q.rows) == 0 {
+		return ErrEmptyRows
+	}
+	return q.checkTenant()
+}
+
+func (q *InsertQuery) appendArgs(args []any) []any {
+	q = q.scoped()
+	if q.rows != nil {
+		for _, row := range q.rows {
+			args = row.appendArgs(args)
+		}
+	} else if q.selectQuery != nil {
+		args = q.selectQuery.appendArgs(args)
+	} else if q.values != nil {
+		args = q.values.appendArgs(args)
+	} else if q.aliasMode != ColonPrefix { // 自动填充的 '?'
+		args = appendPlaceholders(args, len(q.columns))
+	}
+	return q.assignments.appendArgs(args)
+}
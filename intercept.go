@@ -0,0 +1,143 @@
+package sb
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// QueryKind 是语句的类型
+type QueryKind uint8
+
+const (
+	SelectKind QueryKind = iota
+	InsertKind
+	UpdateKind
+	DeleteKind
+)
+
+func (k QueryKind) String() string {
+	switch k {
+	case SelectKind:
+		return "SELECT"
+	case InsertKind:
+		return "INSERT"
+	case UpdateKind:
+		return "UPDATE"
+	case DeleteKind:
+		return "DELETE"
+	}
+	return fmt.Sprintf("QueryKind(%d)", k)
+}
+
+// QueryInfo 是传给 Interceptor 的语句信息，Interceptor 可以修改 SQL 和 Args
+type QueryInfo struct {
+	Kind   QueryKind
+	Tables []string // 语句涉及的表，FROM 和 JOIN 中的表按顺序排列，有库名时为 "schema.name"，不包括子查询中的表
+	SQL    string
+	Args   []any // 在 String、StringE 和 Compile 中为 nil
+}
+
+// Interceptor 在 String、StringE、Build、Compile 和 Compiled.Bind 返回前调用，可以修改语句或返回错误以拒绝生成语句
+type Interceptor func(info *QueryInfo) error
+
+var globalInterceptors atomic.Pointer[[]Interceptor]
+
+// SetInterceptors 替换全局的 Interceptor，不传参数时清空；全局的 Interceptor 先于语句上的 Interceptor 调用
+func SetInterceptors(interceptors ...Interceptor) {
+	globalInterceptors.Store(&interceptors)
+}
+
+// interception 是语句上与 Interceptor 相关的信息，Compile 时会复制到 Compiled 中
+type interception struct {
+	kind         QueryKind
+	table        AnyTable
+	joins        []Join
+	interceptors []Interceptor // 语句上的 Interceptor
+}
+
+// intercept 依次调用全局和语句上的 Interceptor，返回修改后的 SQL 和参数
+func (i interception) intercept(sql string, args []any) (string, []any, error) {
+	var global []Interceptor
+	if p := globalInterceptors.Load(); p != nil {
+		global = *p
+	}
+	if len(global) == 0 && len(i.interceptors) == 0 {
+		return sql, args, nil
+	}
+
+	info := &QueryInfo{Kind: i.kind, Tables: i.tableNames(), SQL: sql, Args: args}
+	for _, interceptors := range [][]Interceptor{global, i.interceptors} {
+		for _, interceptor := range interceptors {
+			if err := interceptor(info); err != nil {
+				return "", nil, err
+			}
+		}
+	}
+	return info.SQL, info.Args, nil
+}
+
+// interceptSQL 用于 String、StringE 和 Compile，调用 Interceptor 时 Args 为 nil
+func (i interception) interceptSQL(sql string) (string, error) {
+	sql, _, err := i.intercept(sql, nil)
+	return sql, err
+}
+
+func (i interception) tableNames() []string {
+	tables := make([]AnyTable, 0, len(i.joins)+1)
+	tables = append(tables, i.table)
+	for _, join := range i.joins {
+		tables = append(tables, join.table)
+	}
+	names := make([]string, 0, len(tables))
+	for _, table := range tables {
+		if _, ok := table.(derivedTable); ok || table == nil {
+			continue
+		}
+		if schema := table.getSchema(); schema != "" {
+			names = append(names, schema+"."+table.getName())
+		} else {
+			names = append(names, table.getName())
+		}
+	}
+	return names
+}
+
+// Intercept 添加只对该语句生效的 Interceptor
+func (q *SelectQuery) Intercept(interceptors ...Interceptor) *SelectQuery {
+	q.interceptors = append(q.interceptors[:len(q.interceptors):len(q.interceptors)], interceptors...) // 不影响 Copy 得到的语句
+	return q
+}
+
+func (q *SelectQuery) interception() interception {
+	return interception{kind: SelectKind, table: q.from.table, joins: q.from.joins, interceptors: q.interceptors}
+}
+
+// Intercept 添加只对该语句生效的 Interceptor
+func (q *InsertQuery) Intercept(interceptors ...Interceptor) *InsertQuery {
+	q.interceptors = append(q.interceptors, interceptors...)
+	return q
+}
+
+func (q *InsertQuery) interception() interception {
+	return interception{kind: InsertKind, table: q.table, interceptors: q.interceptors}
+}
+
+// Intercept 添加只对该语句生效的 Interceptor
+func (q *UpdateQuery) Intercept(interceptors ...Interceptor) *UpdateQuery {
+	q.interceptors = append(q.interceptors, interceptors...)
+	return q
+}
+
+func (q *UpdateQuery) interception() interception {
+	return interception{kind: UpdateKind, table: q.table, interceptors: q.interceptors}
+}
+
+// Intercept 添加只对该语句生效的 Interceptor
+func (q *DeleteQuery) Intercept(interceptors ...Interceptor) *DeleteQuery {
+	q.interceptors = append(q.interceptors, interceptors...)
+	return q
+}
+
+func (q *DeleteQuery) interception() interception {
+	return interception{kind: DeleteKind, table: q.table, interceptors: q.interceptors}
+}
@@ -0,0 +1,179 @@
+package sb
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestInterceptor(t *testing.T) {
+	var infos []QueryInfo
+	SetInterceptors(func(info *QueryInfo) error {
+		infos = append(infos, *info)
+		return nil
+	}, func(info *QueryInfo) error {
+		info.SQL = "/* trace */ " + info.SQL
+		return nil
+	})
+	defer SetInterceptors()
+
+	u := New[UserTable]("u")
+	d := New[DeptTable]("d")
+	l := New[AuditLogTable]("l")
+
+	tests := []struct {
+		query interface {
+			Build(...any) (Statement, error)
+			String() string
+		}
+		kind     QueryKind
+		tables   []string
+		expected string
+		args     []any
+	}{
+		{
+			query:    Select(u.Name, d.Name).FromJoin(u.LeftJoin(d, d.ID.Eq(&u.ID))).Where(u.ID.Eq(Val(1))),
+			kind:     SelectKind,
+			tables:   []string{"user", "dept"},
+			expected: "/* trace */ SELECT `u`.`name`, `d`.`name` FROM `user` AS `u` LEFT JOIN `dept` AS `d` ON `d`.`id` = `u`.`id` WHERE `u`.`id` = ?",
+			args:     []any{1},
+		},
+		{
+			query:    Select(Expr("*")).FromSelect(u.Select(), "t"),
+			kind:     SelectKind,
+			tables:   []string{},
+			expected: "/* trace */ SELECT * FROM (SELECT * FROM `user`) AS `t`",
+		},
+		{
+			query:    l.Insert(l.ID).Values(Val(1)),
+			kind:     InsertKind,
+			tables:   []string{"audit.log"},
+			expected: "/* trace */ INSERT INTO `audit`.`log` (`id`) VALUES (?)",
+			args:     []any{1},
+		},
+		{
+			query:    u.Update(u.Name.Assign(Val("a"))),
+			kind:     UpdateKind,
+			tables:   []string{"user"},
+			expected: "/* trace */ UPDATE `user` SET `name`=?",
+			args:     []any{"a"},
+		},
+		{
+			query:    u.Delete(),
+			kind:     DeleteKind,
+			tables:   []string{"user"},
+			expected: "/* trace */ DELETE `user`",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			infos = nil
+			s, err := test.query.Build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s.SQL != test.expected || !reflect.DeepEqual(s.Args, test.args) {
+				t.Errorf("got %s %v, want %s %v", s.SQL, s.Args, test.expected, test.args)
+			}
+			if got := test.query.String(); got != test.expected {
+				t.Errorf("got %s, want %s", got, test.expected)
+			}
+			if len(infos) != 2 || infos[0].Kind != test.kind || !reflect.DeepEqual(infos[0].Tables, test.tables) || infos[1].Args != nil {
+				t.Errorf("got %+v", infos)
+			}
+		})
+	}
+}
+
+func TestInterceptorVeto(t *testing.T) {
+	errFullTable := errors.New("full table update")
+	SetInterceptors(func(info *QueryInfo) error {
+		if info.Kind == UpdateKind && !strings.Contains(info.SQL, " WHERE ") {
+			return errFullTable
+		}
+		return nil
+	})
+	defer SetInterceptors()
+	u := New[UserTable]("u")
+
+	if _, err := u.Update(u.Name.Assign(PH)).Build("a"); err != errFullTable {
+		t.Errorf("got %v, want %v", err, errFullTable)
+	}
+	if _, err := u.Update(u.Name.Assign(PH)).Where(u.ID.Eq(PH)).Build("a", 1); err != nil {
+		t.Error(err)
+	}
+	compiled := u.Update(u.Name.Assign(PH)).Compile()
+	if _, err := compiled.Bind("a"); err != errFullTable {
+		t.Errorf("got %v, want %v", err, errFullTable)
+	}
+	if _, err := compiled.Args("a"); err != errFullTable {
+		t.Errorf("got %v, want %v", err, errFullTable)
+	}
+	if got := compiled.SQL(); got != "" {
+		t.Errorf("got %s, want empty string", got)
+	}
+	if _, err := u.Update(u.Name.Assign(PH)).StringE(); err != errFullTable {
+		t.Errorf("got %v, want %v", err, errFullTable)
+	}
+	if got := u.Update(u.Name.Assign(PH)).String(); got != "" {
+		t.Errorf("got %s, want empty string", got)
+	}
+	if got, err := u.Update(u.Name.Assign(PH)).Where(u.ID.Eq(PH)).StringE(); err != nil || got != "UPDATE `user` SET `name`=? WHERE `id` = ?" {
+		t.Errorf("got %s, %v", got, err)
+	}
+}
+
+func TestQueryInterceptor(t *testing.T) {
+	u := New[UserTable]("u")
+	var calls []string
+	global := func(info *QueryInfo) error {
+		calls = append(calls, "global")
+		return nil
+	}
+	local := func(info *QueryInfo) error {
+		calls = append(calls, "local")
+		info.Args = append(info.Args, "extra")
+		info.SQL += " AND `name` = ?"
+		return nil
+	}
+	SetInterceptors(global)
+	defer SetInterceptors()
+
+	q := u.Select().Where(u.ID.Eq(PH))
+	c := q.Copy().Intercept(local)
+	s, err := c.Build(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.SQL != "SELECT * FROM `user` WHERE `id` = ? AND `name` = ?" || !reflect.DeepEqual(s.Args, []any{1, "extra"}) {
+		t.Errorf("got %s %v", s.SQL, s.Args)
+	}
+	if !reflect.DeepEqual(calls, []string{"global", "local"}) {
+		t.Errorf("got %v", calls)
+	}
+
+	// 不影响原语句
+	if got := q.String(); got != "SELECT * FROM `user` WHERE `id` = ?" {
+		t.Errorf("got %s", got)
+	}
+
+	compiled := c.Compile() // Compile 时调用一次 Interceptor
+	if compiled.SQL() != "SELECT * FROM `user` WHERE `id` = ? AND `name` = ?" {
+		t.Errorf("got %s", compiled.SQL())
+	}
+	s, err = compiled.Bind(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.SQL != "SELECT * FROM `user` WHERE `id` = ? AND `name` = ?" || !reflect.DeepEqual(s.Args, []any{2, "extra"}) {
+		t.Errorf("got %s %v", s.SQL, s.Args)
+	}
+}
+
+func TestQueryKindString(t *testing.T) {
+	if SelectKind.String() != "SELECT" || DeleteKind.String() != "DELETE" || QueryKind(9).String() != "QueryKind(9)" {
+		t.Error("unexpected QueryKind string")
+	}
+}
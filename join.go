@@ -1,6 +1,13 @@
 package sb
 
-import "bytes"
+import (
+	"bytes"
+	"errors"
+)
+
+// ErrOuterJoinUsing 表示 USING 的外连接中补 NULL 的一侧有租户或软删除条件，
+// 这些条件只能放在 WHERE 中，会把外连接变成内连接，需要改用 ON
+var ErrOuterJoinUsing = errors.New("sb: outer join using on scoped table")
 
 type JoinType uint8
 
@@ -8,7 +15,9 @@ const (
 	InnerJoin JoinType = iota
 	LeftJoin
 	RightJoin
-	OuterJoin
+	CrossJoin
+	StraightJoin // STRAIGHT_JOIN，按书写顺序连接
+	NaturalJoin
 )
 
 type FromTables struct {
@@ -17,52 +26,150 @@ type FromTables struct {
 }
 
 func (f *FromTables) WriteSQL(buf *bytes.Buffer, aliasMode AliasMode) {
+	_, on, _ := f.scopes(NotDeleted, nil)
+	f.writeSQL(buf, aliasMode, on)
+}
+
+// writeSQL 输出 FROM 和 JOIN，on[i] 是由 scopes 得到的需要追加到第 i 个 JOIN 的 ON 中的条件
+func (f *FromTables) writeSQL(buf *bytes.Buffer, aliasMode AliasMode, on [][]Cond) {
 	if f.table == nil {
 		return
 	}
 
-	buf.WriteString(" FROM `")
-	buf.WriteString(f.table.getName())
-	buf.WriteByte('`')
-	if aliasMode != NoAlias {
-		alias := f.table.getAlias()
-		if alias != "" {
-			buf.WriteString(" AS `")
-			buf.WriteString(alias)
-			buf.WriteByte('`')
+	if table, ok := f.table.(derivedTable); ok {
+		buf.WriteString(" FROM ")
+		table.WriteSQL(buf, aliasMode)
+	} else {
+		buf.WriteString(" FROM ")
+		writeTableName(buf, f.table)
+		if aliasMode != NoAlias {
+			alias := f.table.getAlias()
+			if alias != "" {
+				buf.WriteString(" AS `")
+				buf.WriteString(alias)
+				buf.WriteByte('`')
+			}
 		}
+		writeIndexHints(buf, f.table)
 	}
-	for _, join := range f.joins {
-		join.WriteSQL(buf, aliasMode)
+	for i, join := range f.joins {
+		join.writeSQL(buf, aliasMode, on[i])
 	}
 }
 
+func (f *FromTables) appendArgs(args []any) []any {
+	_, on, _ := f.scopes(NotDeleted, nil)
+	return f.appendScopedArgs(args, on)
+}
+
+func (f *FromTables) appendScopedArgs(args []any, on [][]Cond) []any {
+	args = appendArgs(args, f.table)
+	for i, join := range f.joins {
+		if !join.hasOn() {
+			continue
+		}
+		args = appendArgs(args, join.on)
+		for _, cond := range on[i] {
+			args = appendArgs(args, cond)
+		}
+	}
+	return args
+}
+
+// scopes 决定各个表的租户和软删除条件放在哪里，返回需要追加到 WHERE 中的条件和追加到各个 JOIN 的 ON 中的条件：
+// 条件只能放在不会补 NULL 的一侧，否则 LEFT JOIN 会过滤掉左表的行，RIGHT JOIN 会变成内连接。
+//   - LEFT JOIN 和内连接的表的条件放在它自己的 ON 中
+//   - FROM 中的表、RIGHT JOIN 的表以及 USING、NATURAL JOIN 的表的条件放在 WHERE 中，
+//     但之后有 RIGHT JOIN 时，它们在 RIGHT JOIN 中是补 NULL 的一侧，改为放在之后第一个 RIGHT JOIN 的 ON 中
+//   - USING 的外连接没有 ON，补 NULL 的一侧有条件时返回 ErrOuterJoinUsing，条件仍然放在 WHERE 中
+//
+// deleted 是 FROM 中的表的软删除范围，JOIN 的表在 deleted 不为 WithDeleted 时只包含未删除的行
+func (f *FromTables) scopes(deleted DeletedScope, tenant *Value) (where []Cond, on [][]Cond, err error) {
+	var pending []Cond // 等待放入 WHERE 或之后的 RIGHT JOIN 的条件
+	if cond := tenantCond(f.table, tenant); cond != nil {
+		pending = append(pending, cond)
+	}
+	if cond := softDeleteCond(f.table, deleted); cond != nil {
+		pending = append(pending, cond)
+	}
+
+	on = make([][]Cond, len(f.joins))
+	for i, join := range f.joins {
+		conds := join.scopeConds(deleted != WithDeleted, tenant)
+		switch {
+		case join.typ == RightJoin:
+			if join.hasOn() {
+				on[i], pending = pending, nil
+			} else if len(pending) > 0 {
+				err = ErrOuterJoinUsing
+			}
+			pending = append(pending, conds...)
+		case join.hasOn():
+			on[i] = conds
+		default:
+			if join.typ == LeftJoin && len(conds) > 0 {
+				err = ErrOuterJoinUsing
+			}
+			pending = append(pending, conds...)
+		}
+	}
+	return pending, on, err
+}
+
 type Join struct {
 	typ   JoinType
 	table AnyTable
-	on    Condition // 必须是 table1.col1 = table2.col
+	on    Cond    // 可以为空，如 CROSS JOIN
+	using Columns // JOIN ... USING (...)，不能与 on 同时使用
 }
 
 func (j *Join) WriteSQL(buf *bytes.Buffer, aliasMode AliasMode) {
+	j.writeSQL(buf, aliasMode, j.scopeConds(true, nil))
+}
+
+// hasOn 判断是否可以输出 ON，USING 和 NATURAL JOIN 不能有 ON
+func (j *Join) hasOn() bool {
+	return len(j.using) == 0 && j.typ != NaturalJoin
+}
+
+// scopeConds 返回 JOIN 的表的租户和软删除条件，由 FromTables.scopes 决定放在哪里
+func (j *Join) scopeConds(notDeleted bool, tenant *Value) []Cond {
+	var conds []Cond
+	if cond := tenantCond(j.table, tenant); cond != nil {
+		conds = append(conds, cond)
+	}
+	if notDeleted {
+		if cond := softDeleteCond(j.table, NotDeleted); cond != nil {
+			conds = append(conds, cond)
+		}
+	}
+	return conds
+}
+
+// writeSQL 输出 JOIN，scope 是需要追加到 ON 中的条件
+func (j *Join) writeSQL(buf *bytes.Buffer, aliasMode AliasMode, scope []Cond) {
 	if j.table == nil { // 正常情况不会遇到，除非手动构建
 		return
 	}
 
 	switch j.typ {
 	case InnerJoin:
-		buf.WriteString(" JOIN `")
+		buf.WriteString(" JOIN ")
 	case LeftJoin:
-		buf.WriteString(" LEFT JOIN `")
+		buf.WriteString(" LEFT JOIN ")
 	case RightJoin:
-		buf.WriteString(" RIGHT JOIN `")
-	case OuterJoin:
-		buf.WriteString(" OUTER JOIN `")
+		buf.WriteString(" RIGHT JOIN ")
+	case CrossJoin:
+		buf.WriteString(" CROSS JOIN ")
+	case StraightJoin:
+		buf.WriteString(" STRAIGHT_JOIN ")
+	case NaturalJoin:
+		buf.WriteString(" NATURAL JOIN ")
 	default:
 		return
 	}
 
-	buf.WriteString(j.table.getName())
-	buf.WriteByte('`')
+	writeTableName(buf, j.table)
 	if aliasMode != NoAlias {
 		alias := j.table.getAlias()
 		if alias != "" {
@@ -71,43 +178,127 @@ func (j *Join) WriteSQL(buf *bytes.Buffer, aliasMode AliasMode) {
 			buf.WriteByte('`')
 		}
 	}
+	writeIndexHints(buf, j.table)
+
+	if len(j.using) > 0 {
+		buf.WriteString(" USING (")
+		j.using.WriteSQL(buf, NoAlias)
+		buf.WriteByte(')')
+		return
+	}
+	if !j.hasOn() {
+		return
+	}
 
-	buf.WriteString(" ON ")
-	j.on.WriteSQL(buf, aliasMode)
+	conds := scope
+	if j.on != nil {
+		conds = append([]Cond{j.on}, scope...)
+	}
+	for i, cond := range conds {
+		if i == 0 {
+			buf.WriteString(" ON ")
+		} else {
+			buf.WriteString(" AND ")
+		}
+		writeJoinCond(buf, cond, aliasMode)
+	}
 }
 
-func (t *Table) InnerJoin(table AnyTable, on Condition) FromTables {
-	return FromTables{table: t, joins: []Join{{typ: InnerJoin, table: table, on: on}}}
+// writeJoinCond 输出 ON 中的条件，最外层的 AND 不加括号
+func writeJoinCond(buf *bytes.Buffer, cond Cond, aliasMode AliasMode) {
+	conds, ok := cond.(Conditions)
+	if !ok {
+		cond.WriteSQL(buf, aliasMode)
+		return
+	}
+	conds.isTopLevel = false
+	if conds.op != and {
+		conds.WriteSQL(buf, aliasMode)
+		return
+	}
+	for i, c := range conds.conditions {
+		if i > 0 {
+			buf.WriteString(" AND ")
+		}
+		writeJoinCond(buf, c, aliasMode)
+	}
 }
 
-func (t FromTables) InnerJoin(table AnyTable, on Condition) FromTables {
-	t.joins = append(t.joins, Join{typ: InnerJoin, table: table, on: on})
+func (t FromTables) join(j Join) FromTables {
+	t.joins = append(t.joins, j)
 	return t
 }
 
-func (t *Table) LeftJoin(table AnyTable, on Condition) FromTables {
-	return FromTables{table: t, joins: []Join{{typ: LeftJoin, table: table, on: on}}}
+// InnerJoin 的 on 可以是任意条件，如 a.ID.Eq(&b.AID).And(b.Status.Eq(Val(1)))
+func (t FromTables) InnerJoin(table AnyTable, on Cond) FromTables {
+	return t.join(Join{typ: InnerJoin, table: table, on: on})
 }
 
-func (t FromTables) LeftJoin(table AnyTable, on Condition) FromTables {
-	t.joins = append(t.joins, Join{typ: LeftJoin, table: table, on: on})
-	return t
+func (t FromTables) LeftJoin(table AnyTable, on Cond) FromTables {
+	return t.join(Join{typ: LeftJoin, table: table, on: on})
 }
 
-func (t *Table) RightJoin(table AnyTable, on Condition) FromTables {
-	return FromTables{table: t, joins: []Join{{typ: RightJoin, table: table, on: on}}}
+func (t FromTables) RightJoin(table AnyTable, on Cond) FromTables {
+	return t.join(Join{typ: RightJoin, table: table, on: on})
 }
 
-func (t FromTables) RightJoin(table AnyTable, on Condition) FromTables {
-	t.joins = append(t.joins, Join{typ: RightJoin, table: table, on: on})
-	return t
+func (t FromTables) StraightJoin(table AnyTable, on Cond) FromTables {
+	return t.join(Join{typ: StraightJoin, table: table, on: on})
 }
 
-func (t *Table) OuterJoin(table AnyTable, on Condition) FromTables {
-	return FromTables{table: t, joins: []Join{{typ: OuterJoin, table: table, on: on}}}
+func (t FromTables) CrossJoin(table AnyTable) FromTables {
+	return t.join(Join{typ: CrossJoin, table: table})
 }
 
-func (t FromTables) OuterJoin(table AnyTable, on Condition) FromTables {
-	t.joins = append(t.joins, Join{typ: OuterJoin, table: table, on: on})
-	return t
+func (t FromTables) NaturalJoin(table AnyTable) FromTables {
+	return t.join(Join{typ: NaturalJoin, table: table})
+}
+
+// InnerJoinUsing 输出为 JOIN ... USING (`col`, ...)，columns 只使用列名
+func (t FromTables) InnerJoinUsing(table AnyTable, columns ...Column) FromTables {
+	return t.join(Join{typ: InnerJoin, table: table, using: columns})
+}
+
+func (t FromTables) LeftJoinUsing(table AnyTable, columns ...Column) FromTables {
+	return t.join(Join{typ: LeftJoin, table: table, using: columns})
+}
+
+func (t FromTables) RightJoinUsing(table AnyTable, columns ...Column) FromTables {
+	return t.join(Join{typ: RightJoin, table: table, using: columns})
+}
+
+func (t *Table) InnerJoin(table AnyTable, on Cond) FromTables {
+	return FromTables{table: t}.InnerJoin(table, on)
+}
+
+func (t *Table) LeftJoin(table AnyTable, on Cond) FromTables {
+	return FromTables{table: t}.LeftJoin(table, on)
+}
+
+func (t *Table) RightJoin(table AnyTable, on Cond) FromTables {
+	return FromTables{table: t}.RightJoin(table, on)
+}
+
+func (t *Table) StraightJoin(table AnyTable, on Cond) FromTables {
+	return FromTables{table: t}.StraightJoin(table, on)
+}
+
+func (t *Table) CrossJoin(table AnyTable) FromTables {
+	return FromTables{table: t}.CrossJoin(table)
+}
+
+func (t *Table) NaturalJoin(table AnyTable) FromTables {
+	return FromTables{table: t}.NaturalJoin(table)
+}
+
+func (t *Table) InnerJoinUsing(table AnyTable, columns ...Column) FromTables {
+	return FromTables{table: t}.InnerJoinUsing(table, columns...)
+}
+
+func (t *Table) LeftJoinUsing(table AnyTable, columns ...Column) FromTables {
+	return FromTables{table: t}.LeftJoinUsing(table, columns...)
+}
+
+func (t *Table) RightJoinUsing(table AnyTable, columns ...Column) FromTables {
+	return FromTables{table: t}.RightJoinUsing(table, columns...)
 }
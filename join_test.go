@@ -2,6 +2,8 @@ package sb
 
 import (
 	"bytes"
+	"errors"
+	"reflect"
 	"testing"
 )
 
@@ -117,11 +119,11 @@ func TestJoinWriteSQL(t *testing.T) {
 			expected:  " RIGHT JOIN `test` ON `col1` = `col2`",
 		},
 		{
-			typ:       OuterJoin,
+			typ:       StraightJoin,
 			table:     table1,
 			on:        (&Column{name: "col1"}).Eq(Column{name: "col2"}),
 			aliasMode: NoAlias,
-			expected:  " OUTER JOIN `test` ON `col1` = `col2`",
+			expected:  " STRAIGHT_JOIN `test` ON `col1` = `col2`",
 		},
 		{
 			table:     table1,
@@ -154,3 +156,115 @@ func TestJoinWriteSQL(t *testing.T) {
 		})
 	}
 }
+
+func TestJoinKinds(t *testing.T) {
+	u := New[UserTable]("u")
+	d := New[DeptTable]("d")
+	du := New[DeptUserTable]("du")
+	p := New[PostTable]("p")
+	o := New[OrderTable]("o")
+
+	tests := []struct {
+		query    *SelectQuery
+		expected string
+		args     []any
+	}{
+		{
+			query:    Select(u.Name).FromJoin(u.InnerJoin(du, du.UserID.Eq(&u.ID).And(du.DeptID.Eq(Val(1))))),
+			expected: "SELECT `u`.`name` FROM `user` AS `u` JOIN `dept_user` AS `du` ON `du`.`userid` = `u`.`id` AND `du`.`deptid` = ?",
+			args:     []any{1},
+		},
+		{
+			query:    Select(u.Name).FromJoin(u.LeftJoin(du, And(du.UserID.Eq(&u.ID), du.DeptID.Eq(Val(1)).Or(du.DeptID.Eq(Val(2)))))),
+			expected: "SELECT `u`.`name` FROM `user` AS `u` LEFT JOIN `dept_user` AS `du` ON `du`.`userid` = `u`.`id` AND (`du`.`deptid` = ? OR `du`.`deptid` = ?)",
+			args:     []any{1, 2},
+		},
+		{
+			// OR 条件与软删除条件一起使用时需要括号
+			query:    Select(u.Name).FromJoin(u.LeftJoin(p, p.AuthorID.Eq(&u.ID).Or(p.Title.Eq(&u.Name)))),
+			expected: "SELECT `u`.`name` FROM `user` AS `u` LEFT JOIN `post` AS `p` ON (`p`.`author_id` = `u`.`id` OR `p`.`title` = `u`.`name`) AND `p`.`deleted_at` IS NULL",
+		},
+		{
+			query:    Select(u.Name, d.Name).FromJoin(u.CrossJoin(d)),
+			expected: "SELECT `u`.`name`, `d`.`name` FROM `user` AS `u` CROSS JOIN `dept` AS `d`",
+		},
+		{
+			query:    Select(u.Name).FromJoin(u.CrossJoin(p)),
+			expected: "SELECT `u`.`name` FROM `user` AS `u` CROSS JOIN `post` AS `p` ON `p`.`deleted_at` IS NULL",
+		},
+		{
+			query:    Select(u.Name).FromJoin(u.StraightJoin(du, du.UserID.Eq(&u.ID)).StraightJoin(d, d.ID.Eq(&du.DeptID))),
+			expected: "SELECT `u`.`name` FROM `user` AS `u` STRAIGHT_JOIN `dept_user` AS `du` ON `du`.`userid` = `u`.`id` STRAIGHT_JOIN `dept` AS `d` ON `d`.`id` = `du`.`deptid`",
+		},
+		{
+			query:    Select(u.Name).FromJoin(u.NaturalJoin(d)),
+			expected: "SELECT `u`.`name` FROM `user` AS `u` NATURAL JOIN `dept` AS `d`",
+		},
+		{
+			query:    Select(u.Name).FromJoin(u.InnerJoinUsing(d, d.ID, d.Name).LeftJoinUsing(du, du.UserID).RightJoinUsing(p, p.ID)),
+			expected: "SELECT `u`.`name` FROM `user` AS `u` JOIN `dept` AS `d` USING (`id`, `name`) LEFT JOIN `dept_user` AS `du` USING (`userid`) RIGHT JOIN `post` AS `p` USING (`id`) WHERE `p`.`deleted_at` IS NULL",
+		},
+		{
+			// USING 和 NATURAL JOIN 的表的条件追加在 WHERE 中
+			query:    Select(u.Name).FromJoin(u.NaturalJoin(o).LeftJoin(p, p.AuthorID.Eq(&u.ID))).Where(u.ID.Gt(Val(1))).Tenant(7),
+			expected: "SELECT `u`.`name` FROM `user` AS `u` NATURAL JOIN `order` AS `o` LEFT JOIN `post` AS `p` ON `p`.`author_id` = `u`.`id` AND `p`.`deleted_at` IS NULL WHERE `u`.`id` > ? AND `o`.`tenant_id` = ?",
+			args:     []any{1, 7},
+		},
+		{
+			query:    Select(u.Name).FromJoin(u.IndexHints(UseIndex("PRIMARY")).CrossJoin(d).NaturalJoin(du).InnerJoinUsing(p, p.ID)).WithDeleted(),
+			expected: "SELECT `u`.`name` FROM `user` AS `u` USE INDEX (`PRIMARY`) CROSS JOIN `dept` AS `d` NATURAL JOIN `dept_user` AS `du` JOIN `post` AS `p` USING (`id`)",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			s, err := test.query.Build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s.SQL != test.expected {
+				t.Errorf("got %s, want %s", s.SQL, test.expected)
+			}
+			if !reflect.DeepEqual(s.Args, test.args) {
+				t.Errorf("got %v, want %v", s.Args, test.args)
+			}
+		})
+	}
+}
+
+func TestOuterJoinUsing(t *testing.T) {
+	u := New[UserTable]("u")
+	d := New[DeptTable]("d")
+	p := New[PostTable]("p")
+	o := New[OrderTable]("o")
+
+	// USING 没有 ON，补 NULL 的一侧有条件时只能放在 WHERE 中，会变成内连接
+	tests := []struct {
+		query *SelectQuery
+		err   bool
+	}{
+		{query: Select(u.Name).FromJoin(u.LeftJoinUsing(p, p.ID)), err: true},
+		{query: Select(u.Name).FromJoin(u.LeftJoinUsing(p, p.ID)).WithDeleted()},
+		{query: Select(u.Name).FromJoin(u.LeftJoinUsing(o, o.ID)).Tenant(7), err: true},
+		{query: Select(u.Name).FromJoin(u.LeftJoinUsing(o, o.ID))},
+		{query: Select(u.Name).FromJoin(p.RightJoinUsing(d, d.ID)), err: true},
+		{query: Select(u.Name).FromJoin(u.RightJoinUsing(p, p.ID))},
+		{query: Select(u.Name).FromJoin(u.InnerJoinUsing(p, p.ID))},
+		{query: u.Select().Where(u.ID.In(Select(p.AuthorID).FromJoin(p.RightJoinUsing(d, d.ID)))), err: true},
+	}
+
+	for i, test := range tests {
+		_, err := test.query.Build()
+		if test.err != errors.Is(err, ErrOuterJoinUsing) {
+			t.Errorf("%d: Build got %v", i, err)
+		}
+		_, err = test.query.StringE()
+		if test.err != errors.Is(err, ErrOuterJoinUsing) {
+			t.Errorf("%d: StringE got %v", i, err)
+		}
+		_, err = test.query.Compile().Args()
+		if test.err != errors.Is(err, ErrOuterJoinUsing) {
+			t.Errorf("%d: Compile got %v", i, err)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package sb
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+var ErrInvalidCursor = errors.New("sb: invalid cursor")
+
+// Cursor 是上一页边界行中 ORDER BY 各列的值，顺序与 ORDER BY 相同
+type Cursor []any
+
+// Encode 将 cursor 编码为不透明的字符串，可以直接返回给客户端
+func (c Cursor) Encode() (string, error) {
+	data, err := json.Marshal([]any(c))
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func DecodeCursor(s string) (Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var values []any
+	if err = decoder.Decode(&values); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	for i, v := range values {
+		if n, ok := v.(json.Number); ok { // 尽量还原成整数，避免大整数丢失精度
+			if values[i], err = n.Int64(); err != nil {
+				if values[i], err = n.Float64(); err != nil {
+					return nil, ErrInvalidCursor
+				}
+			}
+		}
+	}
+	return values, nil
+}
+
+// SeekAfter 返回 cursor 之后一页的查询语句，不会修改 q。
+// 返回的参数需要追加到 q 原有的参数之后；q 需要设置 ORDER BY，且最后一列应当是唯一的
+func (q *SelectQuery) SeekAfter(cursor Cursor) (*SelectQuery, []any, error) {
+	return q.seek(cursor, false)
+}
+
+// SeekBefore 返回 cursor 之前一页的查询语句，不会修改 q。
+// 返回的语句使用了相反的排序，得到结果后需要用 Reverse 还原顺序
+func (q *SelectQuery) SeekBefore(cursor Cursor) (*SelectQuery, []any, error) {
+	return q.seek(cursor, true)
+}
+
+func (q *SelectQuery) seek(cursor Cursor, backward bool) (*SelectQuery, []any, error) {
+	if len(cursor) == 0 || len(cursor) != len(q.orderBys) {
+		return nil, nil, ErrInvalidCursor
+	}
+
+	query := q.Copy()
+	cond, args := keysetCond(q.orderBys, cursor, backward)
+	query.where = andWhere(q.where, cond)
+	if backward {
+		orderBys := make(OrderBys, len(q.orderBys))
+		for i, orderBy := range q.orderBys {
+			orderBys[i] = OrderBy{column: orderBy.column, desc: !orderBy.desc}
+		}
+		query.orderBys = orderBys
+	}
+	return query, args, nil
+}
+
+// keysetCond 生成 seek 条件：
+// 排序方向一致时使用行比较 (a, b) > (?, ?)；
+// 否则展开为 a > ? OR (a = ? AND b < ?)
+func keysetCond(orderBys OrderBys, values []any, backward bool) (Cond, []any) {
+	desc := orderBys[0].desc
+	sameDirection := true
+	for _, orderBy := range orderBys[1:] {
+		if orderBy.desc != desc {
+			sameDirection = false
+			break
+		}
+	}
+
+	if sameDirection {
+		if len(orderBys) == 1 {
+			return seekCondition(orderBys[0], backward), values
+		}
+		columns := make(Tuple, len(orderBys))
+		placeholders := make(Tuple, len(orderBys))
+		for i, orderBy := range orderBys {
+			columns[i] = orderBy.column
+			placeholders[i] = PH
+		}
+		op := ">"
+		if desc != backward {
+			op = "<"
+		}
+		return Condition{op: op, lv: columns, rv: placeholders}, values
+	}
+
+	conditions := make([]Cond, len(orderBys))
+	args := make([]any, 0, len(orderBys)*(len(orderBys)+1)/2)
+	for i, orderBy := range orderBys {
+		if i == 0 {
+			conditions[i] = seekCondition(orderBy, backward)
+			args = append(args, values[0])
+			continue
+		}
+		and := make([]Cond, i+1)
+		for j := 0; j < i; j++ {
+			and[j] = orderBys[j].column.Eq(PH)
+			args = append(args, values[j])
+		}
+		and[i] = seekCondition(orderBy, backward)
+		args = append(args, values[i])
+		conditions[i] = And(and...)
+	}
+	return Or(conditions...), args
+}
+
+func seekCondition(orderBy OrderBy, backward bool) Condition {
+	if orderBy.desc != backward {
+		return orderBy.column.Lt(PH)
+	}
+	return orderBy.column.Gt(PH)
+}
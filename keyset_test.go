@@ -0,0 +1,109 @@
+package sb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSeek(t *testing.T) {
+	u := New[UserTable]("")
+
+	tests := []struct {
+		query    *SelectQuery
+		cursor   Cursor
+		backward bool
+		expected string
+		args     []any
+	}{
+		{
+			query:    u.Select().OrderBy(u.ID.Asc()).Limit(10),
+			cursor:   Cursor{1},
+			expected: "SELECT * FROM `user` WHERE `id` > ? ORDER BY `id` LIMIT 10",
+			args:     []any{1},
+		},
+		{
+			query:    u.Select().OrderBy(u.ID.Asc()).Limit(10),
+			cursor:   Cursor{1},
+			backward: true,
+			expected: "SELECT * FROM `user` WHERE `id` < ? ORDER BY `id` DESC LIMIT 10",
+			args:     []any{1},
+		},
+		{
+			query:    u.Select().Where(u.Name.Ne(PH)).OrderBy(u.Name.Desc(), u.ID.Desc()).Limit(10),
+			cursor:   Cursor{"a", 1},
+			expected: "SELECT * FROM `user` WHERE `name` != ? AND (`name`, `id`) < (?, ?) ORDER BY `name` DESC, `id` DESC LIMIT 10",
+			args:     []any{"a", 1},
+		},
+		{
+			query:    u.Select().Where(Or(u.Name.Eq(PH), u.Name.Eq(nil))).OrderBy(u.Name.Desc(), u.ID.Desc()),
+			cursor:   Cursor{"a", 1},
+			backward: true,
+			expected: "SELECT * FROM `user` WHERE (`name` = ? OR `name` IS NULL) AND (`name`, `id`) > (?, ?) ORDER BY `name`, `id`",
+			args:     []any{"a", 1},
+		},
+		{
+			query:    u.Select().OrderBy(u.Name.Asc(), u.ID.Desc()),
+			cursor:   Cursor{"a", 1},
+			expected: "SELECT * FROM `user` WHERE (`name` > ? OR (`name` = ? AND `id` < ?)) ORDER BY `name`, `id` DESC",
+			args:     []any{"a", "a", 1},
+		},
+		{
+			query:    u.Select().OrderBy(u.Name.Asc(), u.ID.Desc(), u.Name.Asc()),
+			cursor:   Cursor{"a", 1, "b"},
+			backward: true,
+			expected: "SELECT * FROM `user` WHERE (`name` < ? OR (`name` = ? AND `id` > ?) OR (`name` = ? AND `id` = ? AND `name` < ?)) ORDER BY `name` DESC, `id`, `name` DESC",
+			args:     []any{"a", "a", 1, "a", 1, "b"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			origin := test.query.String()
+			var (
+				query *SelectQuery
+				args  []any
+				err   error
+			)
+			if test.backward {
+				query, args, err = test.query.SeekBefore(test.cursor)
+			} else {
+				query, args, err = test.query.SeekAfter(test.cursor)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := query.String(); got != test.expected {
+				t.Errorf("got %s, want %s", got, test.expected)
+			}
+			if !reflect.DeepEqual(args, test.args) {
+				t.Errorf("got %v, want %v", args, test.args)
+			}
+			if got := test.query.String(); got != origin {
+				t.Errorf("got %s, want %s", got, origin)
+			}
+		})
+	}
+
+	if _, _, err := u.Select().OrderBy(u.ID.Asc()).SeekAfter(Cursor{1, 2}); err != ErrInvalidCursor {
+		t.Errorf("got %v, want %v", err, ErrInvalidCursor)
+	}
+}
+
+func TestCursor(t *testing.T) {
+	cursor := Cursor{int64(1) << 60, "a", 1.5, nil}
+	s, err := cursor.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := DecodeCursor(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, cursor) {
+		t.Errorf("got %v, want %v", got, cursor)
+	}
+
+	if _, err = DecodeCursor("!"); err != ErrInvalidCursor {
+		t.Errorf("got %v, want %v", err, ErrInvalidCursor)
+	}
+}
@@ -0,0 +1,336 @@
+package sb
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type ChangeKind uint8
+
+const (
+	CreateTableChange ChangeKind = iota
+	AddColumnChange
+	ModifyColumnChange
+	DropColumnChange
+	AddIndexChange
+	DropIndexChange
+)
+
+type Change struct {
+	Kind        ChangeKind
+	Table       string
+	Name        string // 列名或索引名，主键为空
+	SQL         string
+	Destructive bool   // 可能造成数据丢失或执行失败，如删除列、修改列类型或改为 NOT NULL
+	Reason      string // 修改列或索引的原因
+}
+
+// Migration 是将现有表结构修改为目标表结构所需的变更，变更已经按可执行的顺序排列
+type Migration struct {
+	Changes []Change
+	Hints   []string // 无法自动判断的情况，如可能是重命名的列
+}
+
+func (m *Migration) Statements() []string {
+	statements := make([]string, len(m.Changes))
+	for i, change := range m.Changes {
+		statements[i] = change.SQL
+	}
+	return statements
+}
+
+func (m *Migration) Destructive() []Change {
+	var changes []Change
+	for _, change := range m.Changes {
+		if change.Destructive {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// Report 返回用于 dry-run 的报告，格式为 SQL 脚本，说明和提示会作为注释输出
+func (m *Migration) Report() string {
+	var buf bytes.Buffer
+	for _, hint := range m.Hints {
+		buf.WriteString("-- HINT: ")
+		buf.WriteString(hint)
+		buf.WriteByte('\n')
+	}
+	for _, change := range m.Changes {
+		if change.Destructive {
+			buf.WriteString("-- DESTRUCTIVE: ")
+			buf.WriteString(change.Reason)
+			buf.WriteByte('\n')
+		} else if change.Reason != "" {
+			buf.WriteString("-- ")
+			buf.WriteString(change.Reason)
+			buf.WriteByte('\n')
+		}
+		buf.WriteString(change.SQL)
+		buf.WriteString(";\n")
+	}
+	return buf.String()
+}
+
+// DiffSchemas 比较多个表，target 中新增的表会生成 CREATE TABLE；只存在于 current 中的表不会被删除
+func DiffSchemas(current, target []*TableSchema) *Migration {
+	m := &Migration{}
+	for _, t := range target {
+		var c *TableSchema
+		for _, table := range current {
+			if table.Name == t.Name {
+				c = table
+				break
+			}
+		}
+		if c == nil {
+			m.Changes = append(m.Changes, Change{Kind: CreateTableChange, Table: t.Name, SQL: CreateTableFromSchema(t).String()})
+			continue
+		}
+		diff := DiffSchema(c, t)
+		m.Changes = append(m.Changes, diff.Changes...)
+		m.Hints = append(m.Hints, diff.Hints...)
+	}
+	return m
+}
+
+// DiffSchema 比较同一个表的现有结构 current 和目标结构 target（如 SchemaOf[T]() 的结果），
+// 生成 ALTER TABLE 语句，顺序为：删除索引、添加列、修改列、删除列、添加索引。
+// target 中未指定的字符集和排序规则不做比较
+func DiffSchema(current, target *TableSchema) *Migration {
+	m := &Migration{}
+	table := target.Name
+	ref := quoteTableName(target)
+
+	var dropIndexes, addIndexes []Change
+	for _, c := range current.Indexes {
+		t := findIndex(target, c)
+		if t != nil && sameIndex(c, t) {
+			continue
+		}
+		reason := "index removed"
+		if t != nil {
+			reason = "index changed"
+			addIndexes = append(addIndexes, addIndexChange(table, ref, t, reason))
+		}
+		dropIndexes = append(dropIndexes, dropIndexChange(table, ref, c, reason))
+	}
+	for _, t := range target.Indexes {
+		if findIndex(current, t) == nil {
+			addIndexes = append(addIndexes, addIndexChange(table, ref, t, ""))
+		}
+	}
+
+	var added, dropped []*ColumnSchema
+	var addColumns, modifyColumns, dropColumns []Change
+	for i, t := range target.Columns {
+		c := current.Column(t.Name)
+		if c == nil {
+			added = append(added, t)
+			addColumns = append(addColumns, Change{
+				Kind:  AddColumnChange,
+				Table: table,
+				Name:  t.Name,
+				SQL:   alterTableSQL(ref, "ADD COLUMN ", t, columnPosition(target, i)),
+			})
+			continue
+		}
+		if reason := columnDifference(c, t); reason != "" {
+			modifyColumns = append(modifyColumns, Change{
+				Kind:        ModifyColumnChange,
+				Table:       table,
+				Name:        t.Name,
+				SQL:         alterTableSQL(ref, "MODIFY COLUMN ", t, ""),
+				Destructive: normalizeType(c.Type) != normalizeType(t.Type) || (c.Nullable && !t.Nullable),
+				Reason:      reason,
+			})
+		}
+	}
+	for _, c := range current.Columns {
+		if target.Column(c.Name) == nil {
+			dropped = append(dropped, c)
+			dropColumns = append(dropColumns, Change{
+				Kind:        DropColumnChange,
+				Table:       table,
+				Name:        c.Name,
+				SQL:         "ALTER TABLE " + ref + " DROP COLUMN `" + c.Name + "`",
+				Destructive: true,
+				Reason:      "drop column " + c.Name,
+			})
+		}
+	}
+
+	for _, d := range dropped { // 定义相同的删除列和新增列可能是重命名
+		for _, a := range added {
+			renamed := *a
+			renamed.Comment = d.Comment // 不比较注释
+			if columnDifference(d, &renamed) == "" {
+				m.Hints = append(m.Hints, fmt.Sprintf("column %s.%s may have been renamed to %s, consider ALTER TABLE %s RENAME COLUMN `%s` TO `%s` instead of dropping it", table, d.Name, a.Name, ref, d.Name, a.Name))
+			}
+		}
+	}
+
+	m.Changes = append(m.Changes, dropIndexes...)
+	m.Changes = append(m.Changes, addColumns...)
+	m.Changes = append(m.Changes, modifyColumns...)
+	m.Changes = append(m.Changes, dropColumns...)
+	m.Changes = append(m.Changes, addIndexes...)
+	return m
+}
+
+func columnPosition(table *TableSchema, i int) string {
+	if i == 0 {
+		return " FIRST"
+	}
+	return " AFTER `" + table.Columns[i-1].Name + "`"
+}
+
+// quoteTableName 返回带反引号的表名，有库名时为 `schema`.`name`
+func quoteTableName(schema *TableSchema) string {
+	if schema.Schema == "" {
+		return "`" + schema.Name + "`"
+	}
+	return "`" + schema.Schema + "`.`" + schema.Name + "`"
+}
+
+func alterTableSQL(ref, action string, column *ColumnSchema, position string) string {
+	buf := pool.Get().(*bytes.Buffer)
+	buf.Reset()
+
+	buf.WriteString("ALTER TABLE ")
+	buf.WriteString(ref)
+	buf.WriteByte(' ')
+	buf.WriteString(action)
+	writeColumnDefinition(buf, column)
+	buf.WriteString(position)
+
+	sql := buf.String()
+	pool.Put(buf)
+	return sql
+}
+
+func findIndex(table *TableSchema, index *IndexSchema) *IndexSchema {
+	for _, i := range table.Indexes {
+		if index.Kind == PrimaryKey {
+			if i.Kind == PrimaryKey {
+				return i
+			}
+		} else if i.Kind != PrimaryKey && i.Name == index.Name {
+			return i
+		}
+	}
+	return nil
+}
+
+func sameIndex(a, b *IndexSchema) bool {
+	if a.Kind != b.Kind || len(a.Columns) != len(b.Columns) {
+		return false
+	}
+	for i := range a.Columns {
+		if a.Columns[i] != b.Columns[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func addIndexChange(table, ref string, index *IndexSchema, reason string) Change {
+	buf := pool.Get().(*bytes.Buffer)
+	buf.Reset()
+
+	buf.WriteString("ALTER TABLE ")
+	buf.WriteString(ref)
+	buf.WriteString(" ADD ")
+	writeIndexDefinition(buf, index)
+
+	sql := buf.String()
+	pool.Put(buf)
+	return Change{Kind: AddIndexChange, Table: table, Name: index.Name, SQL: sql, Reason: reason}
+}
+
+func dropIndexChange(table, ref string, index *IndexSchema, reason string) Change {
+	sql := "ALTER TABLE " + ref + " DROP INDEX `" + index.Name + "`"
+	if index.Kind == PrimaryKey {
+		sql = "ALTER TABLE " + ref + " DROP PRIMARY KEY"
+	}
+	return Change{Kind: DropIndexChange, Table: table, Name: index.Name, SQL: sql, Reason: reason}
+}
+
+// columnDifference 返回 current 和 target 的第一处差异，没有差异时返回空字符串
+func columnDifference(current, target *ColumnSchema) string {
+	switch {
+	case normalizeType(current.Type) != normalizeType(target.Type):
+		return fmt.Sprintf("type of %s changed from %s to %s", target.Name, current.Type, target.Type)
+	case current.Nullable != target.Nullable:
+		if target.Nullable {
+			return fmt.Sprintf("%s changed to NULL", target.Name)
+		}
+		return fmt.Sprintf("%s changed to NOT NULL", target.Name)
+	case normalizeDefault(current.Default) != normalizeDefault(target.Default):
+		return fmt.Sprintf("default of %s changed", target.Name)
+	case current.AutoIncrement != target.AutoIncrement:
+		return fmt.Sprintf("auto_increment of %s changed", target.Name)
+	case normalizeExpression(current.OnUpdate) != normalizeExpression(target.OnUpdate):
+		return fmt.Sprintf("on update of %s changed", target.Name)
+	case target.Charset != "" && !strings.EqualFold(current.Charset, target.Charset):
+		return fmt.Sprintf("charset of %s changed", target.Name)
+	case target.Collate != "" && !strings.EqualFold(current.Collate, target.Collate):
+		return fmt.Sprintf("collate of %s changed", target.Name)
+	case current.Comment != target.Comment:
+		return fmt.Sprintf("comment of %s changed", target.Name)
+	}
+	return ""
+}
+
+var intDisplayWidth = regexp.MustCompile(`^(tinyint|smallint|mediumint|int|bigint)\(\d+\)`)
+
+// normalizeType 统一类型的写法，MySQL 8 的整数类型不再显示宽度（tinyint(1) 除外）
+func normalizeType(typ string) string {
+	typ = strings.ToLower(strings.Join(strings.Fields(typ), " "))
+	typ = strings.ReplaceAll(typ, ", ", ",")
+	switch {
+	case typ == "bool" || typ == "boolean":
+		return "tinyint(1)"
+	case strings.HasPrefix(typ, "integer"):
+		typ = "int" + typ[len("integer"):]
+	}
+	if !strings.HasPrefix(typ, "tinyint(1)") {
+		typ = intDisplayWidth.ReplaceAllString(typ, "$1")
+	}
+	return typ
+}
+
+// normalizeDefault 统一默认值的写法，未设置默认值和 DEFAULT NULL 视为相同，'0' 和 0 视为相同
+func normalizeDefault(value *string) string {
+	if value == nil {
+		return "NULL"
+	}
+	v := *value
+	if len(v) >= 2 && v[0] == '\'' && v[len(v)-1] == '\'' {
+		v = strings.ReplaceAll(v[1:len(v)-1], "''", "'")
+		if n, err := strconv.ParseFloat(v, 64); err == nil {
+			return strconv.FormatFloat(n, 'g', -1, 64)
+		}
+		return "'" + v
+	}
+	return normalizeExpression(v)
+}
+
+func normalizeExpression(expr string) string {
+	expr = strings.ToUpper(strings.TrimSpace(expr))
+	switch expr {
+	case "CURRENT_TIMESTAMP()", "NOW()", "LOCALTIME", "LOCALTIME()", "LOCALTIMESTAMP", "LOCALTIMESTAMP()":
+		return "CURRENT_TIMESTAMP"
+	}
+	if strings.HasPrefix(expr, "(") && strings.HasSuffix(expr, ")") { // 表达式默认值
+		return expr
+	}
+	if n, err := strconv.ParseFloat(expr, 64); err == nil { // 数字和字符串形式的数字视为相同
+		return strconv.FormatFloat(n, 'g', -1, 64)
+	}
+	return expr
+}
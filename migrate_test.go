@@ -0,0 +1,121 @@
+package sb
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiffSchema(t *testing.T) {
+	current, err := ParseDDL("CREATE TABLE `account` (" +
+		"`id` bigint(20) unsigned NOT NULL AUTO_INCREMENT, " +
+		"`tenant_id` int(11) NOT NULL DEFAULT '0', " +
+		"`name` varchar(32) NOT NULL DEFAULT '', " +
+		"`nick` text, " +
+		"`bio` text, " +
+		"`updated_at` datetime DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP, " +
+		"PRIMARY KEY (`id`), KEY `uk_name` (`name`), KEY `idx_old` (`nick`(10))" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	target, err := SchemaOf[AccountTable]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	current[0].Column("tenant_id").Default = nil // 与 target 相同
+	current[0].Column("bio").Type = "mediumtext"
+
+	m := DiffSchema(current[0], target)
+	expected := []string{
+		"ALTER TABLE `account` DROP INDEX `uk_name`",
+		"ALTER TABLE `account` DROP INDEX `idx_old`",
+		"ALTER TABLE `account` MODIFY COLUMN `name` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin NOT NULL DEFAULT '' COMMENT 'it''s a name'",
+		"ALTER TABLE `account` MODIFY COLUMN `bio` text",
+		"ALTER TABLE `account` DROP COLUMN `nick`",
+		"ALTER TABLE `account` ADD UNIQUE KEY `uk_name` (`tenant_id`, `name`)",
+		"ALTER TABLE `account` ADD KEY `idx_tenant` (`tenant_id`)",
+		"ALTER TABLE `account` ADD FULLTEXT KEY `bio` (`bio`)",
+	}
+	if got := m.Statements(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(expected, "\n"))
+	}
+
+	var destructive []string
+	for _, change := range m.Destructive() {
+		destructive = append(destructive, change.Name)
+	}
+	if !reflect.DeepEqual(destructive, []string{"name", "bio", "nick"}) {
+		t.Errorf("got %v", destructive)
+	}
+	if len(m.Hints) != 0 {
+		t.Errorf("got %v", m.Hints)
+	}
+
+	report := m.Report()
+	if !strings.Contains(report, "-- DESTRUCTIVE: drop column nick\nALTER TABLE `account` DROP COLUMN `nick`;\n") || !strings.Contains(report, "-- index changed\nALTER TABLE `account` ADD UNIQUE KEY") {
+		t.Errorf("got:\n%s", report)
+	}
+}
+
+func TestDiffSchemaRename(t *testing.T) {
+	tables, err := ParseDDL("CREATE TABLE `a` (`id` int NOT NULL, `old_name` varchar(10) DEFAULT NULL, PRIMARY KEY (`id`));" +
+		"CREATE TABLE `a` (`new_name` varchar(10), `id` int NOT NULL, PRIMARY KEY (`id`));" +
+		"CREATE TABLE `b` (`id` int NOT NULL)")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := DiffSchemas(tables[:1], tables[1:])
+	expected := []string{
+		"ALTER TABLE `a` ADD COLUMN `new_name` varchar(10) FIRST",
+		"ALTER TABLE `a` DROP COLUMN `old_name`",
+		"CREATE TABLE `b` (`id` int NOT NULL)",
+	}
+	if got := m.Statements(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(expected, "\n"))
+	}
+	if len(m.Hints) != 1 || !strings.Contains(m.Hints[0], "RENAME COLUMN `old_name` TO `new_name`") {
+		t.Errorf("got %v", m.Hints)
+	}
+
+	if m = DiffSchema(tables[1], tables[1]); len(m.Changes) != 0 {
+		t.Errorf("got %v", m.Statements())
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	types := map[string]string{
+		"INT(11)":               "int",
+		"bigint(20) unsigned":   "bigint unsigned",
+		"tinyint(1)":            "tinyint(1)",
+		"tinyint(4)":            "tinyint",
+		"boolean":               "tinyint(1)",
+		"integer":               "int",
+		"decimal(10, 2)":        "decimal(10,2)",
+		"varchar(10)":           "varchar(10)",
+		"enum('a', 'b')":        "enum('a','b')",
+		"mediumint(8) zerofill": "mediumint zerofill",
+	}
+	for typ, expected := range types {
+		if got := normalizeType(typ); got != expected {
+			t.Errorf("%s: got %s, want %s", typ, got, expected)
+		}
+	}
+
+	defaults := [][2]*string{
+		{nil, strPtr("NULL")},
+		{strPtr("'0'"), strPtr("0")},
+		{strPtr("'1.50'"), strPtr("1.5")},
+		{strPtr("now()"), strPtr("CURRENT_TIMESTAMP")},
+		{strPtr("'it''s'"), strPtr("'it''s'")},
+	}
+	for _, pair := range defaults {
+		if normalizeDefault(pair[0]) != normalizeDefault(pair[1]) {
+			t.Errorf("%v != %v", normalizeDefault(pair[0]), normalizeDefault(pair[1]))
+		}
+	}
+	if normalizeDefault(strPtr("'NULL'")) == normalizeDefault(nil) {
+		t.Error("'NULL' == NULL")
+	}
+}
@@ -0,0 +1,65 @@
+package sb
+
+import (
+	"strings"
+	"sync/atomic"
+	"unicode"
+)
+
+// NamingStrategy 根据字段名生成未写 db tag 时的列名
+type NamingStrategy func(field string) string
+
+var (
+	LowerCase NamingStrategy = strings.ToLower                            // UserName -> username，默认
+	SnakeCase NamingStrategy = snakeCase                                  // UserID -> user_id，HTTPServer -> http_server
+	AsIs      NamingStrategy = func(field string) string { return field } // UserName -> UserName
+)
+
+var namingStrategy atomic.Value // NamingStrategy
+
+func init() {
+	namingStrategy.Store(LowerCase)
+}
+
+// SetNamingStrategy 设置全局的命名方式，对 New[T] 和 ScanAll 等扫描函数生效，
+// 应在程序启动时、创建表对象之前调用，已缓存的表元数据和扫描计划会被清空
+func SetNamingStrategy(strategy NamingStrategy) {
+	namingStrategy.Store(strategy)
+	tableMetas.Range(func(key, _ any) bool {
+		tableMetas.Delete(key)
+		return true
+	})
+	scanPlans.Range(func(key, _ any) bool {
+		scanPlans.Delete(key)
+		return true
+	})
+}
+
+func defaultNamingStrategy() NamingStrategy {
+	return namingStrategy.Load().(NamingStrategy)
+}
+
+// snakeCase 将驼峰形式转为下划线形式，连续的大写字母视为一个缩写词，缩写词后的 s 视为复数：UserIDs -> user_ids
+func snakeCase(field string) string {
+	runes := []rune(field)
+	var b strings.Builder
+	b.Grow(len(field) + 4)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				prev := runes[i-1]
+				nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+				if nextLower && runes[i+1] == 's' && (i+2 == len(runes) || !unicode.IsLower(runes[i+2])) {
+					nextLower = false // 缩写词的复数，如 IDs
+				}
+				if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+					b.WriteByte('_')
+				}
+			}
+			b.WriteRune(unicode.ToLower(r))
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
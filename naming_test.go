@@ -0,0 +1,92 @@
+package sb
+
+import (
+	"database/sql/driver"
+	"strings"
+	"testing"
+)
+
+func TestSnakeCase(t *testing.T) {
+	tests := map[string]string{
+		"":           "",
+		"ID":         "id",
+		"Name":       "name",
+		"UserName":   "user_name",
+		"UserID":     "user_id",
+		"UserIDs":    "user_ids",
+		"HTTPServer": "http_server",
+		"URLsValid":  "urls_valid",
+		"Address2":   "address2",
+		"OAuth2Code": "o_auth2_code",
+		"APIKey":     "api_key",
+		"user_name":  "user_name",
+	}
+	for field, expected := range tests {
+		if got := SnakeCase(field); got != expected {
+			t.Errorf("%s: got %s, want %s", field, got, expected)
+		}
+	}
+}
+
+type NamingTable struct {
+	Table     `db:"naming"`
+	ID        Column
+	UserName  Column
+	CreatedAt Column `db:"ctime"`
+}
+
+type namingRow struct {
+	UserName string
+	Dept     struct{ DeptName string } `db:"d"`
+	Extra    struct{ ExtraName string }
+}
+
+func TestNamingStrategy(t *testing.T) {
+	defer SetNamingStrategy(LowerCase)
+
+	n := New[NamingTable]("")
+	if got := Select(n.Columns()).From(n).String(); got != "SELECT `id`, `username`, `ctime` FROM `naming`" {
+		t.Errorf("got %s", got)
+	}
+
+	SetNamingStrategy(SnakeCase)
+	n = New[NamingTable]("")
+	if got := Select(n.Columns()).From(n).String(); got != "SELECT `id`, `user_name`, `ctime` FROM `naming`" {
+		t.Errorf("got %s", got)
+	}
+
+	fake := newFakeDB()
+	db := fake.open()
+	defer db.Close()
+	fake.set("SELECT", fakeResult{
+		columns: []string{"user_name", "d.dept_name", "extra.extra_name"},
+		rows:    [][]driver.Value{{"a", "b", "c"}},
+	})
+	rows, err := db.Query("SELECT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	row, err := ScanOne[namingRow](rows, StrictScan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if row.UserName != "a" || row.Dept.DeptName != "b" || row.Extra.ExtraName != "c" {
+		t.Errorf("got %+v", row)
+	}
+
+	// 单个表的设置优先于全局设置
+	RegisterTable[NamingTable](WithNaming(strings.ToUpper))
+	n = New[NamingTable]("")
+	if got := Select(n.Columns()).From(n).String(); got != "SELECT `ID`, `USERNAME`, `ctime` FROM `naming`" {
+		t.Errorf("got %s", got)
+	}
+	SetNamingStrategy(AsIs)
+	if RegisterTable[NamingTable]().Columns[1].Name != "USERNAME" {
+		t.Error("table option lost")
+	}
+	RegisterTable[NamingTable](WithNaming(AsIs))
+	n = New[NamingTable]("")
+	if got := Select(n.Columns()).From(n).String(); got != "SELECT `ID`, `UserName`, `ctime` FROM `naming`" {
+		t.Errorf("got %s", got)
+	}
+}
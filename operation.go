@@ -21,3 +21,8 @@ func (o Operation) WriteSQL(buf *bytes.Buffer, aliasMode AliasMode) {
 		o.rv.WriteSQL(buf, aliasMode)
 	}
 }
+
+func (o Operation) appendArgs(args []any) []any {
+	args = appendArgs(args, o.lv)
+	return appendArgs(args, o.rv)
+}
@@ -0,0 +1,151 @@
+package sb
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"strings"
+)
+
+const countTableAlias = "t"
+
+// derivedTable 用于 FROM (SELECT ...) AS alias
+type derivedTable struct {
+	query *SelectQuery
+	alias string
+}
+
+func (t derivedTable) isTable() {}
+
+func (t derivedTable) getSchema() string { return "" }
+
+func (t derivedTable) getName() string { return t.alias }
+
+func (t derivedTable) getAlias() string { return t.alias }
+
+func (t derivedTable) getMeta() *TableMeta { return nil }
+
+func (t derivedTable) WriteSQL(buf *bytes.Buffer, aliasMode AliasMode) {
+	buf.WriteByte('(')
+	t.query.WriteSQL(buf, t.query.aliasMode())
+	buf.WriteString(") AS `")
+	buf.WriteString(t.alias)
+	buf.WriteByte('`')
+}
+
+func (t derivedTable) appendArgs(args []any) []any {
+	return t.query.appendArgs(args)
+}
+
+type Pagination struct {
+	Query *SelectQuery // 带 LIMIT 的查询语句
+	Count *SelectQuery // 对应的 COUNT(*) 语句，参数需要用 CountArgs 转换
+	page  uint64
+	size  uint64
+	skip  int // Count 中去掉的 SELECT 列表里未绑定值的 ? 的数量
+}
+
+// CountArgs 将 Query 的参数转换为 Count 的参数：计数语句可能去掉了 SELECT 列表，
+// 其中未绑定值的 ? 位于语句开头，对应的参数会被去掉，sql.NamedArg 保持不变
+func (p Pagination) CountArgs(args ...any) []any {
+	if p.skip == 0 {
+		return args
+	}
+	countArgs := make([]any, 0, len(args))
+	skipped := 0
+	for _, arg := range args {
+		if _, ok := arg.(sql.NamedArg); !ok && skipped < p.skip {
+			skipped++
+			continue
+		}
+		countArgs = append(countArgs, arg)
+	}
+	return countArgs
+}
+
+// Paginate 返回第 page 页（从 1 开始）的查询语句和对应的计数语句，不会修改 q
+func (q *SelectQuery) Paginate(page, size uint64) Pagination {
+	if page == 0 {
+		page = 1
+	}
+	query := q.Copy()
+	query.limit = size
+	query.offset = (page - 1) * size
+	skip := 0
+	if !q.hasDistinct() { // 只有 DISTINCT 时计数语句保留了 SELECT 列表
+		skip = countPlaceholders(q.expressions.appendArgs(nil))
+	}
+	return Pagination{Query: query, Count: q.CountQuery(), page: page, size: size, skip: skip}
+}
+
+// CountQuery 返回计算 q 的结果总数的语句，会去掉 ORDER BY、LIMIT 和锁；
+// 有 GROUP BY 或 DISTINCT 时，会将原语句作为子查询，以计算分组或去重后的数量。
+// 没有 DISTINCT 时 SELECT 列表会被去掉，其中未绑定值的 ? 不再需要参数，见 Pagination.CountArgs
+func (q *SelectQuery) CountQuery() *SelectQuery {
+	inner := q.Copy()
+	inner.orderBys = nil
+	inner.limit = 0
+	inner.offset = 0
+	inner.lockMode = NoLock
+
+	count := Func("COUNT", Expr("*"))
+	if q.hasDistinct() {
+		return Select(count).FromSelect(inner, countTableAlias)
+	}
+	if len(q.groupBys) > 0 {
+		inner.expressions = Expressions{Expr("1")} // 避免子查询中出现重名的列
+		return Select(count).FromSelect(inner, countTableAlias)
+	}
+	inner.expressions = Expressions{count}
+	return inner
+}
+
+func (q *SelectQuery) hasDistinct() bool {
+	for _, e := range q.expressions {
+		switch e := e.(type) {
+		case *Function:
+			if strings.EqualFold(e.Name, "DISTINCT") {
+				return true
+			}
+		case Expr:
+			if len(e) >= 8 && strings.EqualFold(string(e[:8]), "DISTINCT") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// FetchPage 先执行计数语句，有结果时再执行查询语句，args 是查询语句的参数，参数规则与 Build 相同，
+// 计数语句的参数由 CountArgs 得到
+func FetchPage[T any](ctx context.Context, db Queryer, p Pagination, mode ScanMode, args ...any) ([]T, uint64, error) {
+	count, err := p.Count.Build(p.CountArgs(args...)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	rows, err := db.QueryContext(ctx, count.SQL, count.Args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := ScanOne[uint64](rows, StrictScan)
+	if err != nil {
+		return nil, 0, err
+	}
+	if total == 0 || (p.size > 0 && (p.page-1)*p.size >= total) { // 超出范围时无需查询
+		return nil, total, nil
+	}
+
+	query, err := p.Query.Build(args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	rows, err = db.QueryContext(ctx, query.SQL, query.Args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	items, err := ScanAll[T](rows, mode)
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
@@ -0,0 +1,165 @@
+package sb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"testing"
+)
+
+func TestPaginate(t *testing.T) {
+	u1 := New[UserTable]("u1")
+	du := New[DeptUserTable]("du")
+
+	tests := []struct {
+		query    *SelectQuery
+		page     uint64
+		size     uint64
+		expected string
+		count    string
+	}{
+		{
+			query:    u1.Select().Where(u1.Name.Eq(PH)).OrderBy(u1.ID.Desc()).LockForUpdate(),
+			page:     1,
+			size:     10,
+			expected: "SELECT * FROM `user` WHERE `name` = ? ORDER BY `id` DESC LIMIT 10 FOR UPDATE",
+			count:    "SELECT COUNT(*) FROM `user` WHERE `name` = ?",
+		},
+		{
+			query:    u1.Select(u1.ID, u1.Name).Limit(5).Offset(5),
+			page:     3,
+			size:     20,
+			expected: "SELECT `id`, `name` FROM `user` LIMIT 40, 20",
+			count:    "SELECT COUNT(*) FROM `user`",
+		},
+		{
+			query:    u1.Select().Limit(5),
+			page:     0,
+			size:     20,
+			expected: "SELECT * FROM `user` LIMIT 20",
+			count:    "SELECT COUNT(*) FROM `user`",
+		},
+		{
+			query:    Select(u1).FromJoin(u1.InnerJoin(du, u1.ID.Eq(du.UserID))).Where(du.DeptID.Eq(PH)).OrderBy(u1.ID.Asc()),
+			page:     2,
+			size:     10,
+			expected: "SELECT `u1`.* FROM `user` AS `u1` JOIN `dept_user` AS `du` ON `u1`.`id` = `du`.`userid` WHERE `du`.`deptid` = ? ORDER BY `u1`.`id` LIMIT 10, 10",
+			count:    "SELECT COUNT(*) FROM `user` AS `u1` JOIN `dept_user` AS `du` ON `u1`.`id` = `du`.`userid` WHERE `du`.`deptid` = ?",
+		},
+		{
+			query:    Select(u1.Name, Func("COUNT", Expr("1"))).From(u1).GroupBy(u1.Name).OrderBy(u1.Name.Asc()),
+			page:     1,
+			size:     10,
+			expected: "SELECT `name`, COUNT(1) FROM `user` GROUP BY `name` ORDER BY `name` LIMIT 10",
+			count:    "SELECT COUNT(*) FROM (SELECT 1 FROM `user` GROUP BY `name`) AS `t`",
+		},
+		{
+			query:    Select(Func("DISTINCT", u1.Name)).From(u1).Where(u1.ID.Gt(PH)),
+			page:     1,
+			size:     10,
+			expected: "SELECT DISTINCT(`name`) FROM `user` WHERE `id` > ? LIMIT 10",
+			count:    "SELECT COUNT(*) FROM (SELECT DISTINCT(`name`) FROM `user` WHERE `id` > ?) AS `t`",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			origin := test.query.String()
+			p := test.query.Paginate(test.page, test.size)
+			if got := p.Query.String(); got != test.expected {
+				t.Errorf("got %s, want %s", got, test.expected)
+			}
+			if got := p.Count.String(); got != test.count {
+				t.Errorf("got %s, want %s", got, test.count)
+			}
+			if got := test.query.String(); got != origin {
+				t.Errorf("got %s, want %s", got, origin)
+			}
+		})
+	}
+}
+
+func TestFetchPage(t *testing.T) {
+	fake := newFakeDB()
+	db := fake.open()
+	defer db.Close()
+
+	u := New[UserTable]("")
+	p := u.Select().Where(u.Name.Eq(PH)).OrderBy(u.ID.Asc()).Paginate(2, 1)
+	fake.set(p.Count.String(), fakeResult{columns: []string{"COUNT(*)"}, rows: [][]driver.Value{{int64(3)}}})
+	fake.set(p.Query.String(), fakeResult{columns: []string{"id", "name"}, rows: [][]driver.Value{{int64(2), "a"}}})
+
+	items, total, err := FetchPage[userRow](context.Background(), db, p, StrictScan, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 3 {
+		t.Errorf("got %d, want 3", total)
+	}
+	if len(items) != 1 || items[0].ID != 2 {
+		t.Errorf("got %+v", items)
+	}
+	for _, call := range fake.executed {
+		if len(call.args) != 1 || call.args[0] != "a" {
+			t.Errorf("got %v, want [a]", call.args)
+		}
+	}
+
+	fake.executed = nil
+	p = u.Select().Where(u.Name.Eq(PH)).OrderBy(u.ID.Asc()).Paginate(4, 1)
+	items, total, err = FetchPage[userRow](context.Background(), db, p, StrictScan, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 3 || items != nil {
+		t.Errorf("got %d %+v", total, items)
+	}
+	if len(fake.executed) != 1 {
+		t.Errorf("got %d queries, want 1", len(fake.executed))
+	}
+}
+
+func TestFetchPageSelectArgs(t *testing.T) {
+	fake := newFakeDB()
+	db := fake.open()
+	defer db.Close()
+
+	u := New[UserTable]("")
+	tests := []struct {
+		query *SelectQuery
+		count []driver.Value
+	}{
+		{
+			query: u.Select(Func("IF", u.Name.Eq(PH), Expr("1"), Expr("0")), u.ID).Where(u.ID.Gt(PH)),
+			count: []driver.Value{int64(5)},
+		},
+		{
+			query: u.Select(Func("IF", u.Name.Eq(PH), Expr("1"), Expr("0")), u.ID).Where(u.ID.Gt(PH)).GroupBy(u.ID),
+			count: []driver.Value{int64(5)},
+		},
+		{
+			query: u.Select(Func("DISTINCT", Func("CONCAT", u.Name, PH))).Where(u.ID.Gt(PH)),
+			count: []driver.Value{"a", int64(5)},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query.String(), func(t *testing.T) {
+			fake.executed = nil
+			p := test.query.Paginate(1, 10)
+			fake.set(p.Count.String(), fakeResult{columns: []string{"COUNT(*)"}, rows: [][]driver.Value{{int64(0)}}})
+			if _, _, err := FetchPage[userRow](context.Background(), db, p, StrictScan, "a", 5); err != nil {
+				t.Fatal(err)
+			}
+			if len(fake.executed) != 1 || !reflect.DeepEqual(fake.executed[0].args, test.count) {
+				t.Errorf("got %v, want %v", fake.executed, test.count)
+			}
+		})
+	}
+
+	p := u.Select(Func("IF", u.Name.Eq(PH), Expr("1"), Param("x"))).Where(u.ID.Gt(PH)).Paginate(1, 10)
+	if got := p.CountArgs("a", sql.Named("x", 1), 5); !reflect.DeepEqual(got, []any{sql.Named("x", 1), 5}) {
+		t.Errorf("got %v", got)
+	}
+}
@@ -0,0 +1,199 @@
+package sb
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// TableMeta 是表结构体的元数据，每个类型只反射一次，New[T] 和 RegisterTable[T] 会自动注册
+type TableMeta struct {
+	Schema  string // db tag 为 "schema.name" 形式时的库名
+	Name    string
+	Type    reflect.Type
+	Tag     reflect.StructTag // 内嵌 Table 字段的 tag
+	Columns []ColumnMeta      // 按字段顺序排列，内嵌 struct 中的列按其所在位置展开
+	Version string            // 乐观锁的版本列，由列的 sb:"version" tag 或 WithVersionColumn 声明
+	Deleted string            // 软删除的列，由列的 sb:"softdelete" tag 或 WithSoftDelete 声明
+	Tenant  string            // 租户列，由列的 sb:"tenant" tag 或 WithTenantColumn 声明
+
+	tableIndex []int
+	shadowed   []ColumnMeta // 与其他字段列名重复而被忽略的字段，New 时赋值为同名的列，避免留下空的 Column
+}
+
+type ColumnMeta struct {
+	Name  string // 列名
+	Field string // 字段名
+	Index []int  // 字段索引，可用于 reflect.Value.FieldByIndex
+	Tag   reflect.StructTag
+}
+
+func (m *TableMeta) Column(name string) (ColumnMeta, bool) {
+	for _, column := range m.Columns {
+		if column.Name == name {
+			return column, true
+		}
+	}
+	return ColumnMeta{}, false
+}
+
+type tableConfig struct {
+	naming  NamingStrategy
+	version string
+	deleted string
+	tenant  string
+}
+
+// TableOption 是 RegisterTable 中针对单个表类型的设置
+type TableOption func(*tableConfig)
+
+// WithNaming 设置表中未写 db tag 的列的命名方式，覆盖 SetNamingStrategy 的全局设置
+func WithNaming(strategy NamingStrategy) TableOption {
+	return func(c *tableConfig) {
+		c.naming = strategy
+	}
+}
+
+// WithVersionColumn 声明乐观锁的版本列，作用与列的 sb:"version" tag 相同
+func WithVersionColumn(column string) TableOption {
+	return func(c *tableConfig) {
+		c.version = column
+	}
+}
+
+// WithSoftDelete 声明软删除的列，作用与列的 sb:"softdelete" tag 相同
+func WithSoftDelete(column string) TableOption {
+	return func(c *tableConfig) {
+		c.deleted = column
+	}
+}
+
+// WithTenantColumn 声明租户列，作用与列的 sb:"tenant" tag 相同
+func WithTenantColumn(column string) TableOption {
+	return func(c *tableConfig) {
+		c.tenant = column
+	}
+}
+
+var (
+	tableMetas   sync.Map // reflect.Type -> *TableMeta
+	tableOptions sync.Map // reflect.Type -> []TableOption
+)
+
+// RegisterTable 注册 T 并返回它的元数据，可以在程序启动时调用，使 RegisteredTables 能列出尚未使用过的表；
+// 指定 opts 时会替换之前的设置并重新生成元数据，应在创建 T 的表对象之前调用
+func RegisterTable[T AnyTable](opts ...TableOption) *TableMeta {
+	rt := reflect.TypeOf((*T)(nil)).Elem()
+	if len(opts) > 0 {
+		tableOptions.Store(rt, opts)
+		tableMetas.Delete(rt)
+	}
+	return tableMetaOf(rt)
+}
+
+// RegisteredTables 返回所有已注册的表，按表名排序
+func RegisteredTables() []*TableMeta {
+	var metas []*TableMeta
+	tableMetas.Range(func(_, value any) bool {
+		metas = append(metas, value.(*TableMeta))
+		return true
+	})
+	sort.Slice(metas, func(i, j int) bool {
+		if metas[i].Name != metas[j].Name {
+			return metas[i].Name < metas[j].Name
+		}
+		return metas[i].Type.String() < metas[j].Type.String()
+	})
+	return metas
+}
+
+// tableMetaOf 返回 rt 的元数据，rt 不符合规范（没有内嵌的 Table）时返回 nil
+func tableMetaOf(rt reflect.Type) *TableMeta {
+	if meta, ok := tableMetas.Load(rt); ok {
+		return meta.(*TableMeta)
+	}
+	if rt.Kind() != reflect.Struct {
+		return nil
+	}
+
+	config := tableConfig{naming: defaultNamingStrategy()}
+	if opts, ok := tableOptions.Load(rt); ok {
+		for _, opt := range opts.([]TableOption) {
+			opt(&config)
+		}
+	}
+
+	meta := &TableMeta{Type: rt}
+	for i := 0; i < rt.NumField(); i++ {
+		if fi := rt.Field(i); fi.Anonymous && fi.Type == tableType {
+			meta.Name = fi.Tag.Get("db")
+			if schema, name, ok := strings.Cut(meta.Name, "."); ok {
+				meta.Schema, meta.Name = schema, name
+			}
+			meta.Tag = fi.Tag
+			meta.tableIndex = fi.Index
+			break
+		}
+	}
+	if meta.tableIndex == nil {
+		return nil
+	}
+
+	positions := map[string]int{} // 列名 -> 在 meta.Columns 中的位置
+	walkColumnFields(rt, nil, "", config.naming, func(column ColumnMeta) {
+		if i, ok := positions[column.Name]; ok { // 列名重复时层级浅的字段优先，层级相同时先定义的字段优先
+			if len(meta.Columns[i].Index) > len(column.Index) {
+				meta.Columns[i], column = column, meta.Columns[i]
+			}
+			meta.shadowed = append(meta.shadowed, column)
+			return
+		}
+		positions[column.Name] = len(meta.Columns)
+		meta.Columns = append(meta.Columns, column)
+	})
+
+	meta.Version = config.version
+	meta.Deleted = config.deleted
+	meta.Tenant = config.tenant
+	for _, column := range meta.Columns {
+		for _, option := range parseTagOptions(column.Tag.Get("sb")) {
+			if option.key == "version" && meta.Version == "" {
+				meta.Version = column.Name
+			} else if option.key == "softdelete" && meta.Deleted == "" {
+				meta.Deleted = column.Name
+			} else if option.key == "tenant" && meta.Tenant == "" {
+				meta.Tenant = column.Name
+			}
+		}
+	}
+	actual, _ := tableMetas.LoadOrStore(rt, meta)
+	return actual.(*TableMeta)
+}
+
+// walkColumnFields 按字段顺序收集 Column 字段，内嵌的 struct 会被展开，
+// 内嵌字段可以用 sb:"prefix:xxx_" 为其中的列名加上前缀，多层内嵌时前缀依次拼接
+func walkColumnFields(rt reflect.Type, index []int, prefix string, naming NamingStrategy, collect func(ColumnMeta)) {
+	for i := 0; i < rt.NumField(); i++ {
+		fi := rt.Field(i)
+		fieldIndex := make([]int, len(index)+1)
+		copy(fieldIndex, index)
+		fieldIndex[len(index)] = i
+
+		switch {
+		case isColumnType(fi.Type):
+			if fi.IsExported() {
+				collect(ColumnMeta{Name: prefix + columnName(fi, naming), Field: fi.Name, Index: fieldIndex, Tag: fi.Tag})
+			}
+		case fi.Anonymous && fi.Type.Kind() == reflect.Struct && fi.Type != tableType:
+			// 未导出的内嵌 struct 中导出的字段仍然可以被设置
+			fieldPrefix := prefix
+			for _, option := range parseTagOptions(fi.Tag.Get("sb")) {
+				if option.key == "prefix" {
+					fieldPrefix += option.value
+				}
+			}
+			walkColumnFields(fi.Type, fieldIndex, fieldPrefix, naming, collect)
+		}
+	}
+}
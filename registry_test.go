@@ -0,0 +1,96 @@
+package sb
+
+import (
+	"reflect"
+	"testing"
+)
+
+type RegistryTable struct {
+	Table    `db:"registry"`
+	ID       Column `db:"id" ddl:"type:int"`
+	UserName Column
+	Note     string
+	hidden   Column
+}
+
+func TestTableMeta(t *testing.T) {
+	meta := RegisterTable[RegistryTable]()
+	if meta.Name != "registry" || meta.Type != reflect.TypeOf(RegistryTable{}) {
+		t.Fatalf("got %+v", meta)
+	}
+	expected := []ColumnMeta{
+		{Name: "id", Field: "ID", Index: []int{1}, Tag: `db:"id" ddl:"type:int"`},
+		{Name: "username", Field: "UserName", Index: []int{2}},
+	}
+	if !reflect.DeepEqual(meta.Columns, expected) {
+		t.Errorf("got %+v, want %+v", meta.Columns, expected)
+	}
+	if column, ok := meta.Column("username"); !ok || column.Field != "UserName" {
+		t.Errorf("got %+v", column)
+	}
+	if _, ok := meta.Column("hidden"); ok {
+		t.Error("hidden column found")
+	}
+
+	if RegisterTable[RegistryTable]() != meta {
+		t.Error("meta not cached")
+	}
+	if New[RegistryTable]("r").meta != meta {
+		t.Error("New does not use registered meta")
+	}
+	if RegisterTable[AnyTable]() != nil {
+		t.Error("got meta of interface")
+	}
+
+	New[UserTable]("")
+	var names []string
+	for _, m := range RegisteredTables() {
+		names = append(names, m.Name)
+	}
+	if len(names) < 2 || !contains(names, "registry") || !contains(names, "user") {
+		t.Errorf("got %v", names)
+	}
+	for i := 1; i < len(names); i++ {
+		if names[i-1] > names[i] {
+			t.Errorf("not sorted: %v", names)
+		}
+	}
+}
+
+func TestTableColumns(t *testing.T) {
+	r := New[RegistryTable]("r")
+	if got := Select(r.Columns()).From(r).String(); got != "SELECT `id`, `username` FROM `registry`" {
+		t.Errorf("got %s", got)
+	}
+
+	u := New[UserTable]("u")
+	query := Select(r.Columns()).FromJoin(r.InnerJoin(u, u.ID.Eq(&r.ID)))
+	if got := query.String(); got != "SELECT `r`.`id`, `r`.`username` FROM `registry` AS `r` JOIN `user` AS `u` ON `u`.`id` = `r`.`id`" {
+		t.Errorf("got %s", got)
+	}
+
+	column, ok := r.Column("username")
+	if !ok || column.name != "username" || *column.table != r.Table {
+		t.Errorf("got %+v", column)
+	}
+	if _, ok = r.Column("hidden"); ok {
+		t.Error("hidden column found")
+	}
+
+	var table Table
+	if table.Columns() != nil {
+		t.Error("columns of manual table")
+	}
+	if _, ok = table.Column("id"); ok {
+		t.Error("column of manual table")
+	}
+}
+
+func contains(s []string, v string) bool {
+	for _, item := range s {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,190 @@
+package sb
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+type ScanMode uint8
+
+const (
+	LooseScan  ScanMode = iota // 忽略无法映射到字段的列
+	StrictScan                 // 遇到无法映射到字段的列或重名的列时返回错误
+)
+
+var (
+	ErrUnmappedColumn  = errors.New("sb: unmapped column")
+	ErrDuplicateColumn = errors.New("sb: duplicate column") // 如 join 的两个表都有 id 列，后面的值会覆盖前面的
+)
+
+// Rows 是 *sql.Rows 的子集，便于兼容 sqlx 等库的返回值
+type Rows interface {
+	Columns() ([]string, error)
+	Next() bool
+	Scan(dest ...any) error
+	Err() error
+	Close() error
+}
+
+var (
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	timeType    = reflect.TypeOf(time.Time{})
+	scanPlans   sync.Map // reflect.Type -> map[string][]int
+)
+
+// ScanAll 将结果集的每一行按 db tag 扫描到 T 中，结束后会关闭 rows
+// T 不是 struct 时，结果集只能有一列
+func ScanAll[T any](rows Rows, mode ScanMode) ([]T, error) {
+	defer rows.Close()
+
+	indexes, err := mapColumns[T](rows, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []T
+	dest := make([]any, len(indexes))
+	var discard any
+	for rows.Next() {
+		var v T
+		bindDest(&v, indexes, dest, &discard)
+		if err = rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		result = append(result, v)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ScanOne 只扫描结果集的第一行，没有结果时返回 sql.ErrNoRows，结束后会关闭 rows
+func ScanOne[T any](rows Rows, mode ScanMode) (T, error) {
+	defer rows.Close()
+
+	var v T
+	indexes, err := mapColumns[T](rows, mode)
+	if err != nil {
+		return v, err
+	}
+
+	if !rows.Next() {
+		if err = rows.Err(); err != nil {
+			return v, err
+		}
+		return v, sql.ErrNoRows
+	}
+	dest := make([]any, len(indexes))
+	var discard any
+	bindDest(&v, indexes, dest, &discard)
+	if err = rows.Scan(dest...); err != nil {
+		return v, err
+	}
+	return v, rows.Err()
+}
+
+// mapColumns 返回每一列对应的字段索引，nil 表示丢弃该列
+func mapColumns[T any](rows Rows, mode ScanMode) ([][]int, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	rt := reflect.TypeOf((*T)(nil)).Elem()
+	if !isStructToScan(rt) { // 非 struct 时直接扫描到 T 上
+		if len(columns) != 1 {
+			return nil, fmt.Errorf("sb: scan %d columns into non-struct type %s", len(columns), rt)
+		}
+		return [][]int{{}}, nil
+	}
+
+	plan := getScanPlan(rt)
+	indexes := make([][]int, len(columns))
+	seen := make(map[string]bool, len(columns))
+	for i, column := range columns {
+		index, ok := plan[column]
+		if !ok {
+			if mode == StrictScan {
+				return nil, fmt.Errorf("%w: %s", ErrUnmappedColumn, column)
+			}
+			continue
+		}
+		if seen[column] && mode == StrictScan {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateColumn, column)
+		}
+		seen[column] = true
+		indexes[i] = index
+	}
+	return indexes, nil
+}
+
+func bindDest[T any](v *T, indexes [][]int, dest []any, discard *any) {
+	rv := reflect.ValueOf(v).Elem()
+	for i, index := range indexes {
+		if index == nil {
+			dest[i] = discard
+		} else if len(index) == 0 { // 非 struct
+			dest[i] = v
+		} else {
+			dest[i] = rv.FieldByIndex(index).Addr().Interface()
+		}
+	}
+}
+
+func isStructToScan(rt reflect.Type) bool {
+	return rt.Kind() == reflect.Struct && rt != timeType && !reflect.PointerTo(rt).Implements(scannerType)
+}
+
+func getScanPlan(rt reflect.Type) map[string][]int {
+	if plan, ok := scanPlans.Load(rt); ok {
+		return plan.(map[string][]int)
+	}
+	plan := map[string][]int{}
+	walkScanFields(rt, nil, "", defaultNamingStrategy(), plan)
+	scanPlans.Store(rt, plan)
+	return plan
+}
+
+// walkScanFields 收集 struct 中可扫描的字段：
+// 内嵌且没有 tag 的 struct 会被展开；其他 struct 字段以 "tag." 或 "按命名方式转换的字段名." 作为前缀，用于映射 join 时以 "别名.列名" 命名的列
+func walkScanFields(rt reflect.Type, index []int, prefix string, naming NamingStrategy, plan map[string][]int) {
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		tag := f.Tag.Get("db")
+		if tag == "-" {
+			continue
+		}
+
+		fieldIndex := make([]int, len(index)+1)
+		copy(fieldIndex, index)
+		fieldIndex[len(index)] = i
+
+		if isStructToScan(f.Type) {
+			if f.Anonymous && tag == "" {
+				walkScanFields(f.Type, fieldIndex, prefix, naming, plan)
+				continue
+			}
+			if tag == "" {
+				tag = naming(f.Name)
+			}
+			walkScanFields(f.Type, fieldIndex, prefix+tag+".", naming, plan)
+			continue
+		}
+
+		if tag == "" {
+			tag = naming(f.Name)
+		}
+		name := prefix + tag
+		if _, ok := plan[name]; !ok { // 同名时先定义的字段优先
+			plan[name] = fieldIndex
+		}
+	}
+}
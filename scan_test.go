@@ -0,0 +1,196 @@
+package sb
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+)
+
+type userRow struct {
+	ID        int64  `db:"id"`
+	Name      string `db:"name"`
+	OtherID   *int64 `db:"other_id"`
+	Email     sql.NullString
+	CreatedAt time.Time `db:"created_at"`
+	Ignored   string    `db:"-"`
+	Dept      deptRow   `db:"d"`
+	secret    string
+}
+
+type deptRow struct {
+	ID   int64  `db:"id"`
+	Name string `db:"name"`
+}
+
+type Timestamps struct {
+	UpdatedAt *time.Time `db:"updated_at"`
+}
+
+type userWithTimestamps struct {
+	ID int64 `db:"id"`
+	Timestamps
+}
+
+func TestScanAll(t *testing.T) {
+	fake := newFakeDB()
+	db := fake.open()
+	defer db.Close()
+
+	now := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	fake.set("SELECT", fakeResult{
+		columns: []string{"id", "name", "other_id", "email", "created_at", "d.id", "d.name"},
+		rows: [][]driver.Value{
+			{int64(1), "a", int64(2), "a@b.c", now, int64(10), "dept"},
+			{int64(2), "b", nil, nil, now, int64(11), "dept2"},
+		},
+	})
+
+	rows, err := db.Query("SELECT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	users, err := ScanAll[userRow](rows, StrictScan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 2 {
+		t.Fatalf("got %d rows, want 2", len(users))
+	}
+
+	u := users[0]
+	if u.ID != 1 || u.Name != "a" || u.OtherID == nil || *u.OtherID != 2 || !u.Email.Valid || u.Email.String != "a@b.c" || !u.CreatedAt.Equal(now) {
+		t.Errorf("got %+v", u)
+	}
+	if u.Dept.ID != 10 || u.Dept.Name != "dept" {
+		t.Errorf("got %+v", u.Dept)
+	}
+	u = users[1]
+	if u.OtherID != nil || u.Email.Valid || u.Dept.ID != 11 {
+		t.Errorf("got %+v", u)
+	}
+}
+
+func TestScanAllUnmapped(t *testing.T) {
+	fake := newFakeDB()
+	db := fake.open()
+	defer db.Close()
+
+	fake.set("SELECT", fakeResult{
+		columns: []string{"id", "unknown"},
+		rows:    [][]driver.Value{{int64(1), "x"}},
+	})
+
+	rows, err := db.Query("SELECT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = ScanAll[userRow](rows, StrictScan); !errors.Is(err, ErrUnmappedColumn) {
+		t.Errorf("got %v, want %v", err, ErrUnmappedColumn)
+	}
+
+	rows, err = db.Query("SELECT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	users, err := ScanAll[userRow](rows, LooseScan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 1 || users[0].ID != 1 {
+		t.Errorf("got %+v", users)
+	}
+}
+
+func TestScanOne(t *testing.T) {
+	fake := newFakeDB()
+	db := fake.open()
+	defer db.Close()
+
+	fake.set("COUNT", fakeResult{
+		columns: []string{"COUNT(*)"},
+		rows:    [][]driver.Value{{int64(3)}},
+	})
+	fake.set("EMPTY", fakeResult{
+		columns: []string{"id", "updated_at"},
+	})
+	fake.set("EMBEDDED", fakeResult{
+		columns: []string{"id", "updated_at"},
+		rows:    [][]driver.Value{{int64(1), time.Unix(0, 0)}},
+	})
+
+	rows, err := db.Query("COUNT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	count, err := ScanOne[uint64](rows, StrictScan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 {
+		t.Errorf("got %d, want 3", count)
+	}
+
+	rows, err = db.Query("EMPTY")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = ScanOne[userWithTimestamps](rows, StrictScan); err != sql.ErrNoRows {
+		t.Errorf("got %v, want %v", err, sql.ErrNoRows)
+	}
+
+	rows, err = db.Query("EMBEDDED")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := ScanOne[userWithTimestamps](rows, StrictScan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.ID != 1 || u.UpdatedAt == nil {
+		t.Errorf("got %+v", u)
+	}
+}
+
+func TestScanJoin(t *testing.T) {
+	u := New[UserTable]("u")
+	d := New[DeptTable]("d")
+	query := Select(u, d.PrefixedColumns()).FromJoin(u.InnerJoin(d, d.ID.Eq(&u.ID)))
+	if got, want := query.String(), "SELECT `u`.*, `d`.`id` AS `d.id`, `d`.`name` AS `d.name` FROM `user` AS `u` JOIN `dept` AS `d` ON `d`.`id` = `u`.`id`"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	fake := newFakeDB()
+	db := fake.open()
+	defer db.Close()
+
+	fake.set("PREFIXED", fakeResult{
+		columns: []string{"id", "name", "d.id", "d.name"},
+		rows:    [][]driver.Value{{int64(1), "a", int64(99), "dept"}},
+	})
+	fake.set("DUPLICATE", fakeResult{ // Select(u, d) 的结果
+		columns: []string{"id", "name", "id", "name"},
+		rows:    [][]driver.Value{{int64(1), "a", int64(99), "dept"}},
+	})
+
+	rows, err := db.Query("PREFIXED")
+	if err != nil {
+		t.Fatal(err)
+	}
+	users, err := ScanAll[userRow](rows, StrictScan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 1 || users[0].ID != 1 || users[0].Name != "a" || users[0].Dept.ID != 99 || users[0].Dept.Name != "dept" {
+		t.Errorf("got %+v", users)
+	}
+
+	rows, err = db.Query("DUPLICATE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = ScanAll[userRow](rows, StrictScan); !errors.Is(err, ErrDuplicateColumn) {
+		t.Errorf("got %v, want %v", err, ErrDuplicateColumn)
+	}
+}
@@ -0,0 +1,59 @@
+package sb
+
+type IndexKind uint8
+
+const (
+	NormalIndex IndexKind = iota
+	PrimaryKey
+	UniqueIndex
+	FulltextIndex
+	SpatialIndex
+)
+
+// TableSchema 描述表结构，可以由 CREATE TABLE 语句解析得到
+type TableSchema struct {
+	Schema  string // 库名，为空时使用连接的当前库；SchemaOf 会根据 db tag 设置，ParseDDL 不保留库名
+	Name    string
+	Columns []*ColumnSchema
+	Indexes []*IndexSchema
+	Engine  string
+	Charset string
+	Collate string
+	Comment string
+}
+
+type ColumnSchema struct {
+	Name          string
+	Type          string  // 如 bigint unsigned、varchar(64)
+	Nullable      bool    // 未声明 NOT NULL 时为 true
+	Default       *string // nil 表示未声明默认值；否则为 SQL 表达式，如 'a'、0、NULL、CURRENT_TIMESTAMP
+	AutoIncrement bool
+	OnUpdate      string // ON UPDATE 后的表达式
+	Charset       string
+	Collate       string
+	Comment       string
+}
+
+type IndexSchema struct {
+	Name    string // 主键为空
+	Kind    IndexKind
+	Columns []string
+}
+
+func (t *TableSchema) Column(name string) *ColumnSchema {
+	for _, column := range t.Columns {
+		if column.Name == name {
+			return column
+		}
+	}
+	return nil
+}
+
+func (t *TableSchema) PrimaryKey() *IndexSchema {
+	for _, index := range t.Indexes {
+		if index.Kind == PrimaryKey {
+			return index
+		}
+	}
+	return nil
+}
@@ -14,14 +14,19 @@ const (
 )
 
 type SelectQuery struct {
-	expressions Expressions
-	from        FromTables
-	where       Cond
-	groupBys    Columns
-	orderBys    OrderBys
-	limit       uint64
-	offset      uint64
-	lockMode    LockMode
+	expressions  Expressions
+	from         FromTables
+	where        Cond
+	groupBys     Columns
+	orderBys     OrderBys
+	limit        uint64
+	offset       uint64
+	lockMode     LockMode
+	deleted      DeletedScope
+	tenant       *Value // 由 Tenant 设置的租户
+	interceptors []Interceptor
+	comments     []string
+	hints        []string
 }
 
 func Select(expressions ...Expression) *SelectQuery {
@@ -41,6 +46,12 @@ func (q *SelectQuery) From(from AnyTable) *SelectQuery {
 	return q
 }
 
+// FromSelect 用于 SELECT ... FROM (SELECT ...) AS alias
+func (q *SelectQuery) FromSelect(query *SelectQuery, alias string) *SelectQuery {
+	q.from = FromTables{table: derivedTable{query: query, alias: alias}}
+	return q
+}
+
 func (q *SelectQuery) Where(cond Cond) *SelectQuery {
 	switch cond := cond.(type) {
 	case Condition:
@@ -92,11 +103,15 @@ func (q SelectQuery) Copy() *SelectQuery {
 }
 
 func (q *SelectQuery) WriteSQL(buf *bytes.Buffer, aliasMode AliasMode) {
+	q = q.scoped()
+	writeComments(buf, q.comments)
 	buf.WriteString("SELECT ")
+	writeHints(buf, q.hints)
 	q.expressions.WriteSQL(buf, aliasMode)
-	q.from.WriteSQL(buf, aliasMode)
-	if q.where != nil {
-		q.where.WriteSQL(buf, aliasMode)
+	where, on := q.scopes()
+	q.from.writeSQL(buf, aliasMode, on)
+	if where != nil {
+		where.WriteSQL(buf, aliasMode)
 	}
 	if len(q.groupBys) > 0 {
 		buf.WriteString(" GROUP BY ")
@@ -121,17 +136,75 @@ func (q *SelectQuery) WriteSQL(buf *bytes.Buffer, aliasMode AliasMode) {
 	}
 }
 
+// String 返回经过 Interceptor 处理的 SQL，StringE 返回错误时返回空字符串，主要用于调试和日志
 func (q *SelectQuery) String() string {
+	sql, _ := q.StringE()
+	return sql
+}
+
+// StringE 返回经过 Interceptor 处理的 SQL，Interceptor 拒绝或严格模式下没有设置租户时返回错误
+func (q *SelectQuery) StringE() (string, error) {
+	if err := q.checkTenant(); err != nil {
+		return "", err
+	}
+	return q.interception().interceptSQL(q.render())
+}
+
+func (q *SelectQuery) render() string {
 	buf := pool.Get().(*bytes.Buffer)
 	buf.Reset()
 
-	if len(q.from.joins) > 0 {
-		q.WriteSQL(buf, UseAlias)
-	} else {
-		q.WriteSQL(buf, NoAlias)
-	}
+	q.WriteSQL(buf, q.aliasMode())
 
-	sql := buf.String()
+	sql := sqlString(buf)
 	pool.Put(buf)
 	return sql
 }
+
+// Build 返回 SQL 和参数，args 会按顺序填充未绑定值的 ?，sql.NamedArg 会按名字绑定 Param
+func (q *SelectQuery) Build(args ...any) (Statement, error) {
+	if err := q.checkTenant(); err != nil {
+		return Statement{}, err
+	}
+	slots := q.appendArgs(nil)
+	bound, err := bindArgs(slots[:0], slots, args)
+	if err != nil {
+		return Statement{}, err
+	}
+	sql, bound, err := q.interception().intercept(q.render(), bound)
+	if err != nil {
+		return Statement{}, err
+	}
+	return Statement{SQL: sql, Args: bound}, nil
+}
+
+// Compile 生成 SQL 并返回可以反复绑定参数的 Compiled，之后对 q 的修改不会影响它
+func (q *SelectQuery) Compile() *Compiled {
+	return newCompiled(q.render(), q.appendArgs(nil), q.checkTenant(), q.interception())
+}
+
+func (q *SelectQuery) appendArgs(args []any) []any {
+	q = q.scoped()
+	where, on := q.scopes()
+	args = q.expressions.appendArgs(args)
+	args = q.from.appendScopedArgs(args, on)
+	return appendArgs(args, where)
+}
+
+// scopes 返回追加了租户和软删除条件的 WHERE，以及需要追加到各个 JOIN 的 ON 中的条件，
+// 条件无法正确放置时的错误由 checkOwnTenant 返回
+func (q *SelectQuery) scopes() (Cond, [][]Cond) {
+	conds, on, _ := q.from.scopes(q.deleted, q.tenant)
+	where := q.where
+	for _, cond := range conds {
+		where = andWhere(where, cond)
+	}
+	return where, on
+}
+
+func (q *SelectQuery) aliasMode() AliasMode {
+	if len(q.from.joins) > 0 {
+		return UseAlias
+	}
+	return NoAlias
+}
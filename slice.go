@@ -6,3 +6,9 @@ func Prepend[T any](slice []T, item T) []T {
 	slice[0] = item
 	return slice
 }
+
+func Reverse[T any](slice []T) {
+	for i, j := 0, len(slice)-1; i < j; i, j = i+1, j-1 {
+		slice[i], slice[j] = slice[j], slice[i]
+	}
+}
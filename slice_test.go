@@ -18,3 +18,17 @@ func TestPrepend(t *testing.T) {
 		t.Errorf("Expected %v, got %v", []string{"d", "a", "b", "c"}, stringSlice)
 	}
 }
+
+func TestReverse(t *testing.T) {
+	intSlice := []int{1, 2, 3, 4, 5}
+	Reverse(intSlice)
+	if !reflect.DeepEqual(intSlice, []int{5, 4, 3, 2, 1}) {
+		t.Errorf("Expected %v, got %v", []int{5, 4, 3, 2, 1}, intSlice)
+	}
+
+	stringSlice := []string{"a", "b"}
+	Reverse(stringSlice)
+	if !reflect.DeepEqual(stringSlice, []string{"b", "a"}) {
+		t.Errorf("Expected %v, got %v", []string{"b", "a"}, stringSlice)
+	}
+}
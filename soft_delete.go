@@ -0,0 +1,76 @@
+package sb
+
+// DeletedScope 决定查询如何处理软删除的行
+type DeletedScope uint8
+
+const (
+	NotDeleted  DeletedScope = iota // 只包含未删除的行，默认
+	WithDeleted                     // 包含所有行
+	OnlyDeleted                     // 只包含已删除的行
+)
+
+// softDeleteColumn 返回表的软删除列，表没有声明软删除时返回 nil
+func softDeleteColumn(table AnyTable) *Column {
+	if table == nil {
+		return nil
+	}
+	meta := table.getMeta()
+	if meta == nil || meta.Deleted == "" {
+		return nil
+	}
+	return &Column{name: meta.Deleted, table: asTable(table)}
+}
+
+// softDeleteCond 返回 scope 对应的条件，表没有声明软删除或 scope 为 WithDeleted 时返回 nil
+func softDeleteCond(table AnyTable, scope DeletedScope) Cond {
+	column := softDeleteColumn(table)
+	if column == nil {
+		return nil
+	}
+	switch scope {
+	case NotDeleted:
+		return column.Eq(nil) // `deleted_at` IS NULL
+	case OnlyDeleted:
+		return column.Ne(nil) // `deleted_at` IS NOT NULL
+	}
+	return nil
+}
+
+// andScope 在 where 后追加软删除的条件
+func andScope(where Cond, table AnyTable, scope DeletedScope) Cond {
+	cond := softDeleteCond(table, scope)
+	if cond == nil {
+		return where
+	}
+	return andWhere(where, cond)
+}
+
+// WithDeleted 查询时包含软删除的行，JOIN 的表也不再追加条件
+func (q *SelectQuery) WithDeleted() *SelectQuery {
+	q.deleted = WithDeleted
+	return q
+}
+
+// OnlyDeleted 只查询 FROM 中的表已软删除的行，JOIN 的表仍只包含未删除的行
+func (q *SelectQuery) OnlyDeleted() *SelectQuery {
+	q.deleted = OnlyDeleted
+	return q
+}
+
+// WithDeleted 更新时包含软删除的行
+func (q *UpdateQuery) WithDeleted() *UpdateQuery {
+	q.deleted = WithDeleted
+	return q
+}
+
+// OnlyDeleted 只更新已软删除的行，如恢复：u.Update(u.DeletedAt.Assign(Expr("NULL"))).OnlyDeleted()
+func (q *UpdateQuery) OnlyDeleted() *UpdateQuery {
+	q.deleted = OnlyDeleted
+	return q
+}
+
+// HardDelete 对声明了软删除的表执行真正的 DELETE
+func (q *DeleteQuery) HardDelete() *DeleteQuery {
+	q.hard = true
+	return q
+}
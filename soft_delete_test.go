@@ -0,0 +1,156 @@
+package sb
+
+import "testing"
+
+type PostTable struct {
+	Table     `db:"post"`
+	ID        Column `db:"id"`
+	AuthorID  Column `db:"author_id"`
+	Title     Column `db:"title"`
+	DeletedAt Column `db:"deleted_at" sb:"softdelete"`
+}
+
+type AuthorTable struct {
+	Table   `db:"author"`
+	ID      Column `db:"id"`
+	Name    Column `db:"name"`
+	Removed Column `db:"removed_at"`
+}
+
+func TestSoftDelete(t *testing.T) {
+	RegisterTable[AuthorTable](WithSoftDelete("removed_at"))
+	p := New[PostTable]("p")
+	a := New[AuthorTable]("a")
+	u := New[UserTable]("u")
+
+	tests := []struct {
+		query    interface{ String() string }
+		expected string
+	}{
+		{
+			query:    p.Select(),
+			expected: "SELECT * FROM `post` WHERE `deleted_at` IS NULL",
+		},
+		{
+			query:    p.Select().Where(p.ID.Eq(PH)),
+			expected: "SELECT * FROM `post` WHERE `id` = ? AND `deleted_at` IS NULL",
+		},
+		{
+			query:    p.Select().Where(p.ID.Eq(PH)).WithDeleted(),
+			expected: "SELECT * FROM `post` WHERE `id` = ?",
+		},
+		{
+			query:    p.Select().OnlyDeleted(),
+			expected: "SELECT * FROM `post` WHERE `deleted_at` IS NOT NULL",
+		},
+		{
+			query:    Select(p.Title, a.Name).FromJoin(p.LeftJoin(a, a.ID.Eq(&p.AuthorID))).Where(p.ID.Gt(PH)),
+			expected: "SELECT `p`.`title`, `a`.`name` FROM `post` AS `p` LEFT JOIN `author` AS `a` ON `a`.`id` = `p`.`author_id` AND `a`.`removed_at` IS NULL WHERE `p`.`id` > ? AND `p`.`deleted_at` IS NULL",
+		},
+		{
+			query:    Select(p.Title, a.Name).FromJoin(p.LeftJoin(a, a.ID.Eq(&p.AuthorID))).WithDeleted(),
+			expected: "SELECT `p`.`title`, `a`.`name` FROM `post` AS `p` LEFT JOIN `author` AS `a` ON `a`.`id` = `p`.`author_id`",
+		},
+		{
+			query:    Select(u.Name).FromJoin(u.InnerJoin(p, p.AuthorID.Eq(&u.ID))),
+			expected: "SELECT `u`.`name` FROM `user` AS `u` JOIN `post` AS `p` ON `p`.`author_id` = `u`.`id` AND `p`.`deleted_at` IS NULL",
+		},
+		{
+			query:    p.Update(p.Title.Assign(PH)).Where(p.ID.Eq(PH)),
+			expected: "UPDATE `post` SET `title`=? WHERE `id` = ? AND `deleted_at` IS NULL",
+		},
+		{
+			query:    p.Update(p.DeletedAt.Assign(Expr("NULL"))).Where(p.ID.Eq(PH)).OnlyDeleted(),
+			expected: "UPDATE `post` SET `deleted_at`=NULL WHERE `id` = ? AND `deleted_at` IS NOT NULL",
+		},
+		{
+			query:    p.Update(p.Title.Assign(PH)).WithDeleted(),
+			expected: "UPDATE `post` SET `title`=?",
+		},
+		{
+			query:    p.Delete().Where(p.ID.Eq(PH)),
+			expected: "UPDATE `post` SET `deleted_at`=NOW() WHERE `id` = ? AND `deleted_at` IS NULL",
+		},
+		{
+			query:    a.Delete().Limit(10),
+			expected: "UPDATE `author` SET `removed_at`=NOW() WHERE `removed_at` IS NULL LIMIT 10",
+		},
+		{
+			query:    p.Delete().Where(p.ID.Eq(PH)).HardDelete(),
+			expected: "DELETE `post` WHERE `id` = ?",
+		},
+		{
+			query:    u.Delete().Where(u.ID.Eq(PH)),
+			expected: "DELETE `user` WHERE `id` = ?",
+		},
+		{
+			query:    p.Select().Where(p.ID.Eq(PH)).CountQuery(),
+			expected: "SELECT COUNT(*) FROM `post` WHERE `id` = ? AND `deleted_at` IS NULL",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			if got := test.query.String(); got != test.expected {
+				t.Errorf("got %s, want %s", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestSoftDeleteJoin(t *testing.T) {
+	RegisterTable[AuthorTable](WithSoftDelete("removed_at"))
+	p := New[PostTable]("p")
+	a := New[AuthorTable]("a")
+
+	// 条件只能放在不会补 NULL 的一侧
+	tests := []struct {
+		query    *SelectQuery
+		expected string
+	}{
+		{
+			query:    Select(p.Title).FromJoin(p.InnerJoin(a, a.ID.Eq(&p.AuthorID))),
+			expected: "FROM `post` AS `p` JOIN `author` AS `a` ON `a`.`id` = `p`.`author_id` AND `a`.`removed_at` IS NULL WHERE `p`.`deleted_at` IS NULL",
+		},
+		{
+			query:    Select(p.Title).FromJoin(p.LeftJoin(a, a.ID.Eq(&p.AuthorID))),
+			expected: "FROM `post` AS `p` LEFT JOIN `author` AS `a` ON `a`.`id` = `p`.`author_id` AND `a`.`removed_at` IS NULL WHERE `p`.`deleted_at` IS NULL",
+		},
+		{
+			query:    Select(p.Title).FromJoin(p.RightJoin(a, a.ID.Eq(&p.AuthorID))),
+			expected: "FROM `post` AS `p` RIGHT JOIN `author` AS `a` ON `a`.`id` = `p`.`author_id` AND `p`.`deleted_at` IS NULL WHERE `a`.`removed_at` IS NULL",
+		},
+		{
+			query:    Select(p.Title).FromJoin(p.RightJoin(a, a.ID.Eq(&p.AuthorID))).OnlyDeleted(),
+			expected: "FROM `post` AS `p` RIGHT JOIN `author` AS `a` ON `a`.`id` = `p`.`author_id` AND `p`.`deleted_at` IS NOT NULL WHERE `a`.`removed_at` IS NULL",
+		},
+		{
+			query:    Select(p.Title).FromJoin(p.RightJoin(a, a.ID.Eq(&p.AuthorID))).WithDeleted(),
+			expected: "FROM `post` AS `p` RIGHT JOIN `author` AS `a` ON `a`.`id` = `p`.`author_id`",
+		},
+		{
+			query:    Select(p.Title).FromJoin(p.StraightJoin(a, a.ID.Eq(&p.AuthorID))),
+			expected: "FROM `post` AS `p` STRAIGHT_JOIN `author` AS `a` ON `a`.`id` = `p`.`author_id` AND `a`.`removed_at` IS NULL WHERE `p`.`deleted_at` IS NULL",
+		},
+		{
+			query:    Select(p.Title).FromJoin(p.CrossJoin(a)),
+			expected: "FROM `post` AS `p` CROSS JOIN `author` AS `a` ON `a`.`removed_at` IS NULL WHERE `p`.`deleted_at` IS NULL",
+		},
+		{
+			query:    Select(p.Title).FromJoin(p.NaturalJoin(a)),
+			expected: "FROM `post` AS `p` NATURAL JOIN `author` AS `a` WHERE `p`.`deleted_at` IS NULL AND `a`.`removed_at` IS NULL",
+		},
+		{
+			query:    Select(p.Title).FromJoin(p.InnerJoinUsing(a, a.ID)),
+			expected: "FROM `post` AS `p` JOIN `author` AS `a` USING (`id`) WHERE `p`.`deleted_at` IS NULL AND `a`.`removed_at` IS NULL",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			if got, expected := test.query.String(), "SELECT `p`.`title` "+test.expected; got != expected {
+				t.Errorf("got %s, want %s", got, expected)
+			}
+		})
+	}
+}
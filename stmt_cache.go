@@ -0,0 +1,175 @@
+package sb
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+)
+
+var ErrStmtCacheClosed = errors.New("sb: statement cache is closed")
+
+// StmtCache 缓存预编译的语句，相同的 SQL 只会预编译一次，超出容量时淘汰最久未使用的语句。
+// 每个 *sql.DB 或 *sql.Tx 需要使用单独的 StmtCache；用于 *sql.Tx 时，需要在事务结束前调用 Close()
+type StmtCache struct {
+	db       Preparer
+	capacity int
+
+	mu     sync.Mutex
+	lru    *list.List // *cachedStmt，最近使用的在前面
+	stmts  map[string]*list.Element
+	stats  StmtCacheStats
+	closed bool
+}
+
+type cachedStmt struct {
+	query   string
+	stmt    *sql.Stmt
+	refs    int  // 正在使用该语句的调用方数量
+	evicted bool // 已从缓存中移除，最后一个调用方释放后关闭
+}
+
+type StmtCacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	Size      int
+}
+
+// NewStmtCache 创建最多缓存 capacity 条语句的 StmtCache，capacity <= 0 时不限制数量
+func NewStmtCache(db Preparer, capacity int) *StmtCache {
+	return &StmtCache{
+		db:       db,
+		capacity: capacity,
+		lru:      list.New(),
+		stmts:    map[string]*list.Element{},
+	}
+}
+
+// Prepare 返回 query 对应的预编译语句，调用方不能关闭它，使用完后需要调用 release，
+// 在此之前语句即使被淘汰也不会被关闭
+func (c *StmtCache) Prepare(ctx context.Context, query string) (stmt *sql.Stmt, release func(), err error) {
+	cached, err := c.acquire(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cached.stmt, func() { c.release(cached) }, nil
+}
+
+func (c *StmtCache) acquire(ctx context.Context, query string) (*cachedStmt, error) {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil, ErrStmtCacheClosed
+	}
+	if elem, ok := c.stmts[query]; ok {
+		c.stats.Hits++
+		c.lru.MoveToFront(elem)
+		cached := elem.Value.(*cachedStmt)
+		cached.refs++
+		c.mu.Unlock()
+		return cached, nil
+	}
+	c.stats.Misses++
+	c.mu.Unlock()
+
+	stmt, err := c.db.PrepareContext(ctx, query) // 预编译比较慢，不持有锁
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		stmt.Close()
+		return nil, ErrStmtCacheClosed
+	}
+	if elem, ok := c.stmts[query]; ok { // 其他 goroutine 已经缓存了
+		stmt.Close()
+		c.lru.MoveToFront(elem)
+		cached := elem.Value.(*cachedStmt)
+		cached.refs++
+		return cached, nil
+	}
+	cached := &cachedStmt{query: query, stmt: stmt, refs: 1}
+	c.stmts[query] = c.lru.PushFront(cached)
+	if c.capacity > 0 {
+		for c.lru.Len() > c.capacity {
+			c.evict(c.lru.Back())
+			c.stats.Evictions++
+		}
+	}
+	return cached, nil
+}
+
+func (c *StmtCache) release(cached *cachedStmt) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cached.refs--
+	if cached.refs == 0 && cached.evicted {
+		cached.stmt.Close()
+	}
+}
+
+// evict 从缓存中移除语句，没有调用方正在使用时立即关闭，否则由最后一个调用方释放时关闭；
+// 已经开始执行的查询会在结束后才真正关闭语句（由 database/sql 保证）
+func (c *StmtCache) evict(elem *list.Element) {
+	cached := c.lru.Remove(elem).(*cachedStmt)
+	delete(c.stmts, cached.query)
+	cached.evicted = true
+	if cached.refs == 0 {
+		cached.stmt.Close()
+	}
+}
+
+func (c *StmtCache) ExecContext(ctx context.Context, s Statement) (sql.Result, error) {
+	cached, err := c.acquire(ctx, s.SQL)
+	if err != nil {
+		return nil, err
+	}
+	defer c.release(cached)
+	return cached.stmt.ExecContext(ctx, s.Args...)
+}
+
+func (c *StmtCache) QueryContext(ctx context.Context, s Statement) (*sql.Rows, error) {
+	cached, err := c.acquire(ctx, s.SQL)
+	if err != nil {
+		return nil, err
+	}
+	defer c.release(cached)
+	return cached.stmt.QueryContext(ctx, s.Args...)
+}
+
+func (c *StmtCache) Stats() StmtCacheStats {
+	c.mu.Lock()
+	stats := c.stats
+	stats.Size = c.lru.Len()
+	c.mu.Unlock()
+	return stats
+}
+
+// Close 关闭所有缓存的语句，之后无法再使用
+func (c *StmtCache) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+
+	var err error
+	for elem := c.lru.Front(); elem != nil; elem = elem.Next() {
+		cached := elem.Value.(*cachedStmt)
+		cached.evicted = true
+		if cached.refs > 0 { // 由最后一个调用方释放时关闭
+			continue
+		}
+		if e := cached.stmt.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	c.lru.Init()
+	c.stmts = map[string]*list.Element{}
+	return err
+}
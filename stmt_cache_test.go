@@ -0,0 +1,123 @@
+package sb
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestStmtCache(t *testing.T) {
+	fake := newFakeDB()
+	db := fake.open()
+	defer db.Close()
+
+	u := New[UserTable]("")
+	ctx := context.Background()
+	queries := make([]Statement, 3)
+	for i := range queries {
+		s, err := u.Select().Where(u.ID.Eq(PH)).Limit(uint64(i + 1)).Build(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		queries[i] = s
+		fake.set(s.SQL, fakeResult{columns: []string{"id"}, rows: [][]driver.Value{{int64(i)}}})
+	}
+
+	cache := NewStmtCache(db, 2)
+	for _, i := range []int{0, 1, 0, 2, 1} {
+		rows, err := cache.QueryContext(ctx, queries[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		id, err := ScanOne[int](rows, StrictScan)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id != i {
+			t.Errorf("got %d, want %d", id, i)
+		}
+	}
+
+	stats := cache.Stats()
+	expected := StmtCacheStats{Hits: 1, Misses: 4, Evictions: 2, Size: 2}
+	if stats != expected {
+		t.Errorf("got %+v, want %+v", stats, expected)
+	}
+	if fake.prepared != 4 {
+		t.Errorf("got %d, want 4", fake.prepared)
+	}
+
+	fake.set("DELETE", fakeResult{rowsAffected: 3})
+	result, err := cache.ExecContext(ctx, Statement{SQL: "DELETE"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := result.RowsAffected(); n != 3 {
+		t.Errorf("got %d, want 3", n)
+	}
+
+	if err = cache.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if fake.closed != 5 {
+		t.Errorf("got %d, want 5", fake.closed)
+	}
+	if cache.Stats().Size != 0 {
+		t.Errorf("got %d, want 0", cache.Stats().Size)
+	}
+	if _, err = cache.QueryContext(ctx, queries[0]); err != ErrStmtCacheClosed {
+		t.Errorf("got %v, want %v", err, ErrStmtCacheClosed)
+	}
+}
+
+func TestStmtCacheConcurrentEvict(t *testing.T) {
+	fake := newFakeDB()
+	db := fake.open()
+	defer db.Close()
+
+	queries := make([]Statement, 4)
+	for i := range queries {
+		queries[i] = Statement{SQL: fmt.Sprintf("UPDATE %d", i)}
+		fake.set(queries[i].SQL, fakeResult{rowsAffected: 1})
+	}
+
+	cache := NewStmtCache(db, 1) // 每次未命中都会淘汰其他 goroutine 可能正在使用的语句
+	defer cache.Close()
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				if _, err := cache.ExecContext(ctx, queries[(g+i)%len(queries)]); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	stmt, release, err := cache.Prepare(ctx, queries[0].SQL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = cache.ExecContext(ctx, queries[1]); err != nil { // 淘汰 queries[0]
+		t.Fatal(err)
+	}
+	if _, err = stmt.ExecContext(ctx); err != nil {
+		t.Errorf("evicted statement closed before release: %v", err)
+	}
+	release()
+	if _, err = stmt.ExecContext(ctx); err == nil {
+		t.Error("expected statement to be closed after release")
+	}
+}
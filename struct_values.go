@@ -0,0 +1,265 @@
+package sb
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrEmptyRows 表示 FromStructs 传入的 slice 为空，没有需要插入的行
+var ErrEmptyRows = errors.New("sb: no rows to insert")
+
+type structOptions struct {
+	omitZero          bool
+	omitPrimaryKey    bool
+	omitAutoIncrement bool
+	only              map[string]bool // 列名
+	exclude           map[string]bool
+}
+
+// StructOption 是 FromStruct、FromStructs 和 SetStruct 的选项
+type StructOption func(*structOptions)
+
+// OmitZero 跳过值为零值的字段，FromStructs 中只跳过所有行都为零值的字段
+func OmitZero() StructOption {
+	return func(o *structOptions) {
+		o.omitZero = true
+	}
+}
+
+// OmitPrimaryKey 跳过 ddl tag 中声明了 primary key 的列
+func OmitPrimaryKey() StructOption {
+	return func(o *structOptions) {
+		o.omitPrimaryKey = true
+	}
+}
+
+// OmitAutoIncrement 跳过 ddl tag 中声明了 auto_increment 的列
+func OmitAutoIncrement() StructOption {
+	return func(o *structOptions) {
+		o.omitAutoIncrement = true
+	}
+}
+
+// Only 只使用指定的列，可以多次使用
+func Only(columns ...Column) StructOption {
+	return func(o *structOptions) {
+		if o.only == nil {
+			o.only = map[string]bool{}
+		}
+		for _, column := range columns {
+			o.only[column.name] = true
+		}
+	}
+}
+
+// Exclude 跳过指定的列，可以多次使用
+func Exclude(columns ...Column) StructOption {
+	return func(o *structOptions) {
+		if o.exclude == nil {
+			o.exclude = map[string]bool{}
+		}
+		for _, column := range columns {
+			o.exclude[column.name] = true
+		}
+	}
+}
+
+// structField 是表中的列与 struct 字段的对应关系
+type structField struct {
+	column Column
+	index  []int
+}
+
+// structFields 按表的列顺序返回 rt 中能对应到表中的列的字段，字段按 db tag 匹配列名，规则与 ScanAll 相同；
+// table 不是由 New 创建或 rt 不是 struct 时会 panic
+func structFields(table AnyTable, rt reflect.Type, o *structOptions) []structField {
+	meta := table.getMeta()
+	if meta == nil {
+		panic(fmt.Sprintf("sb: table %s has no metadata, create it with New", table.getName()))
+	}
+	if !isStructToScan(rt) {
+		panic(fmt.Sprintf("sb: %s is not a struct", rt))
+	}
+
+	plan := getScanPlan(rt)
+	t := asTable(table)
+	var fields []structField
+	for _, column := range meta.Columns {
+		index, ok := plan[column.Name]
+		if !ok || (o.only != nil && !o.only[column.Name]) || o.exclude[column.Name] {
+			continue
+		}
+		if o.omitPrimaryKey || o.omitAutoIncrement {
+			skip := false
+			for _, option := range parseTagOptions(column.Tag.Get("ddl")) {
+				if (o.omitPrimaryKey && option.key == "primary key") || (o.omitAutoIncrement && option.key == "auto_increment") {
+					skip = true
+				}
+			}
+			if skip {
+				continue
+			}
+		}
+		fields = append(fields, structField{column: Column{name: column.Name, table: t}, index: index})
+	}
+	return fields
+}
+
+func newStructOptions(opts []StructOption) *structOptions {
+	o := &structOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// FromStruct 根据 v 的字段设置插入的列和值，v 可以是 struct 或指向 struct 的指针，值会作为绑定参数：
+//
+//	u.Insert().FromStruct(user, OmitAutoIncrement()) // INSERT INTO `user` (`name`, `age`) VALUES (?, ?)
+//
+// struct 中的字段按 db tag 对应到表中的列，没有对应字段的列会被忽略
+func (q *InsertQuery) FromStruct(v any, opts ...StructOption) *InsertQuery {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	o := newStructOptions(opts)
+	q.columns = nil
+	q.values = Expressions{}
+	q.rows = nil
+	for _, field := range structFields(q.table, rv.Type(), o) {
+		value := rv.FieldByIndex(field.index)
+		if o.omitZero && value.IsZero() {
+			continue
+		}
+		q.columns = append(q.columns, field.column)
+		q.values = append(q.values, Val(value.Interface()))
+	}
+	return q
+}
+
+// FromStructs 根据 rows 生成插入多行的语句，rows 是 struct 或指向 struct 的指针的 slice；
+// rows 为空时 Build、StringE 和 Compiled.Args 返回 ErrEmptyRows
+func (q *InsertQuery) FromStructs(rows any, opts ...StructOption) *InsertQuery {
+	rv := reflect.ValueOf(rows)
+	if rv.Kind() != reflect.Slice {
+		panic(fmt.Sprintf("sb: FromStructs needs a slice, got %T", rows))
+	}
+	rt := rv.Type().Elem()
+	if rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+
+	o := newStructOptions(opts)
+	q.columns = nil
+	q.values = nil
+	q.rows = make([]Expressions, rv.Len())
+	for _, field := range structFields(q.table, rt, o) {
+		if o.omitZero {
+			allZero := true
+			for i := 0; i < rv.Len() && allZero; i++ {
+				allZero = reflect.Indirect(rv.Index(i)).FieldByIndex(field.index).IsZero()
+			}
+			if allZero {
+				continue
+			}
+		}
+		q.columns = append(q.columns, field.column)
+		for i := range q.rows {
+			q.rows[i] = append(q.rows[i], Val(reflect.Indirect(rv.Index(i)).FieldByIndex(field.index).Interface()))
+		}
+	}
+	return q
+}
+
+// SetStruct 根据 v 的字段追加赋值语句，选项与 FromStruct 相同：
+//
+//	u.Update().SetStruct(user, OmitPrimaryKey(), OmitZero()).Where(u.ID.Eq(PH)) // UPDATE `user` SET `name`=?, `age`=? WHERE `id` = ?
+//
+// 表声明了版本列且 v 中有对应字段时，该字段不会出现在 SET 中，而是以它的值调用 CheckVersion
+func (q *UpdateQuery) SetStruct(v any, opts ...StructOption) *UpdateQuery {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	o := newStructOptions(opts)
+	for _, field := range structFields(q.table, rv.Type(), o) {
+		value := rv.FieldByIndex(field.index)
+		if field.column.name == q.table.getMeta().Version {
+			q.CheckVersion(value.Interface())
+			continue
+		}
+		if o.omitZero && value.IsZero() {
+			continue
+		}
+		column := field.column
+		q.assignments = append(q.assignments, Assignment{column: &column, value: Val(value.Interface())})
+	}
+	return q
+}
+
+// isPrimaryKey 判断列的 ddl tag 中是否声明了 primary key
+func isPrimaryKey(column ColumnMeta) bool {
+	for _, option := range parseTagOptions(column.Tag.Get("ddl")) {
+		if option.key == "primary key" {
+			return true
+		}
+	}
+	return false
+}
+
+// SetChanged 比较 original 和 modified 两个同类型的 struct，只为值不同的字段追加赋值，
+// 并以 original 中主键的值作为 WHERE 条件（在生成 SQL 时与 Where 的条件用 AND 连接，不会被之后的 Where 覆盖）：
+//
+//	u.Update().SetChanged(before, after) // UPDATE `user` SET `name`=? WHERE `id` = ?
+//
+// 主键由 ddl tag 中的 primary key 确定，不会出现在 SET 中；表没有声明主键或 struct 中没有主键字段时，
+// Build、StringE 和 Compiled.Args 返回 ErrNoPrimaryKey。
+// 可以使用 Only 和 Exclude 选项。没有字段变化时 IsNoop() 返回 true，Build() 返回 ErrNoChanges。
+// 表声明了版本列时，以 original 中的版本号调用 CheckVersion
+func (q *UpdateQuery) SetChanged(original, modified any, opts ...StructOption) *UpdateQuery {
+	ov := reflect.Indirect(reflect.ValueOf(original))
+	mv := reflect.Indirect(reflect.ValueOf(modified))
+	if ov.Type() != mv.Type() {
+		panic(fmt.Sprintf("sb: SetChanged with different types %s and %s", ov.Type(), mv.Type()))
+	}
+
+	o := newStructOptions(opts)
+	o.omitZero = false
+	q.keys = nil
+	for _, field := range structFields(q.table, ov.Type(), &structOptions{}) {
+		if meta, _ := q.table.getMeta().Column(field.column.name); isPrimaryKey(meta) {
+			column := field.column
+			q.keys = append(q.keys, column.Eq(Val(ov.FieldByIndex(field.index).Interface())))
+		}
+	}
+	if len(q.keys) == 0 {
+		q.err = fmt.Errorf("%w: table %s, struct %s", ErrNoPrimaryKey, q.table.getName(), ov.Type())
+		return q
+	}
+
+	for _, field := range structFields(q.table, ov.Type(), &structOptions{}) {
+		if field.column.name == q.table.getMeta().Version {
+			q.CheckVersion(ov.FieldByIndex(field.index).Interface())
+		}
+	}
+
+	for _, field := range structFields(q.table, ov.Type(), o) {
+		if meta, _ := q.table.getMeta().Column(field.column.name); isPrimaryKey(meta) || field.column.name == q.table.getMeta().Version {
+			continue
+		}
+		value := mv.FieldByIndex(field.index)
+		if fieldEqual(ov.FieldByIndex(field.index), value) {
+			continue
+		}
+		column := field.column
+		q.assignments = append(q.assignments, Assignment{column: &column, value: Val(value.Interface())})
+	}
+	return q
+}
+
+// fieldEqual 比较两个字段的值，有 Equal 方法的类型（如 time.Time）使用 Equal 比较
+func fieldEqual(a, b reflect.Value) bool {
+	if method := a.MethodByName("Equal"); method.IsValid() {
+		t := method.Type()
+		if t.NumIn() == 1 && t.In(0) == b.Type() && t.NumOut() == 1 && t.Out(0).Kind() == reflect.Bool {
+			return method.Call([]reflect.Value{b})[0].Bool()
+		}
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
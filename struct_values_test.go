@@ -0,0 +1,237 @@
+package sb
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type accountRow struct {
+	ID       int64  `db:"id"`
+	TenantID int    `db:"tenant_id"`
+	Name     string `db:"name"`
+	Bio      *string
+	Extra    string `db:"extra"` // 表中没有的列
+}
+
+func TestFromStruct(t *testing.T) {
+	a := New[AccountTable]("a")
+	bio := "hi"
+	row := accountRow{ID: 1, TenantID: 2, Name: "n", Bio: &bio, Extra: "x"}
+
+	tests := []struct {
+		query interface {
+			Build(...any) (Statement, error)
+		}
+		expected string
+		args     []any
+	}{
+		{
+			query:    a.Insert().FromStruct(row),
+			expected: "INSERT INTO `account` (`id`, `tenant_id`, `name`, `bio`) VALUES (?, ?, ?, ?)",
+			args:     []any{int64(1), 2, "n", &bio},
+		},
+		{
+			query:    a.Insert().FromStruct(&accountRow{Name: "n"}, OmitZero(), OmitAutoIncrement()),
+			expected: "INSERT INTO `account` (`name`) VALUES (?)",
+			args:     []any{"n"},
+		},
+		{
+			query:    a.Insert().FromStruct(row, Only(a.Name, a.Bio), Exclude(a.Bio)).OnDuplicateKeyUpdate(a.Name.Assign(Expr("VALUES(`name`)"))),
+			expected: "INSERT INTO `account` (`name`) VALUES (?) ON DUPLICATE KEY UPDATE `name`=VALUES(`name`)",
+			args:     []any{"n"},
+		},
+		{
+			query:    a.Insert().FromStructs([]accountRow{{TenantID: 1, Name: "a"}, {TenantID: 2}}, OmitZero()),
+			expected: "INSERT INTO `account` (`tenant_id`, `name`) VALUES (?, ?), (?, ?)",
+			args:     []any{1, "a", 2, ""},
+		},
+		{
+			query:    a.Insert().Ignore().FromStructs([]*accountRow{&row}, OmitPrimaryKey(), Only(a.ID, a.Name)),
+			expected: "INSERT IGNORE INTO `account` (`name`) VALUES (?)",
+			args:     []any{"n"},
+		},
+		{
+			query:    a.Update().SetStruct(row, OmitPrimaryKey(), Exclude(a.Bio)).Where(a.ID.Eq(Val(row.ID))),
+			expected: "UPDATE `account` SET `tenant_id`=?, `name`=? WHERE `id` = ?",
+			args:     []any{2, "n", int64(1)},
+		},
+		{
+			query:    a.Update(a.UpdatedAt.Assign(Expr("NOW()"))).SetStruct(accountRow{Name: "m"}, OmitZero()).Where(a.ID.Eq(PH)),
+			expected: "UPDATE `account` SET `updated_at`=NOW(), `name`=? WHERE `id` = ?",
+			args:     []any{"m", 3},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			var args []any
+			if test.args[len(test.args)-1] == 3 {
+				args = []any{3}
+			}
+			s, err := test.query.Build(args...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s.SQL != test.expected {
+				t.Errorf("got %s, want %s", s.SQL, test.expected)
+			}
+			if !reflect.DeepEqual(s.Args, test.args) {
+				t.Errorf("got %v, want %v", s.Args, test.args)
+			}
+		})
+	}
+}
+
+func TestFromStructPanic(t *testing.T) {
+	a := New[AccountTable]("")
+	tests := map[string]func(){
+		"not struct":   func() { a.Insert().FromStruct(1) },
+		"not slice":    func() { a.Insert().FromStructs(accountRow{}) },
+		"manual table": func() { Update(Table{name: "account"}).SetStruct(accountRow{}) },
+	}
+	for name, f := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("not panic")
+				}
+			}()
+			f()
+		})
+	}
+}
+
+func TestFromStructsEmpty(t *testing.T) {
+	a := New[AccountTable]("")
+	tests := []func() error{
+		func() error { _, err := a.Insert().FromStructs([]accountRow{}).Build(); return err },
+		func() error { _, err := a.Insert().FromStructs([]*accountRow(nil)).StringE(); return err },
+		func() error { _, err := a.Insert().FromStructs([]accountRow{}).Compile().Args(); return err },
+	}
+	for i, f := range tests {
+		if err := f(); !errors.Is(err, ErrEmptyRows) {
+			t.Errorf("%d: got %v, want %v", i, err, ErrEmptyRows)
+		}
+	}
+
+	// 之后调用 FromStruct 会替换空的行
+	q := a.Insert().FromStructs([]accountRow{}).FromStruct(accountRow{Name: "n"}, Only(a.Name))
+	if _, err := q.Build(); err != nil {
+		t.Error(err)
+	}
+}
+
+type accountPatch struct {
+	ID        int64     `db:"id"`
+	Name      string    `db:"name"`
+	Bio       *string   `db:"bio"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func TestSetChanged(t *testing.T) {
+	a := New[AccountTable]("a")
+	m := New[MembershipTable]("")
+	bio, bio2 := "a", "a"
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	original := accountPatch{ID: 1, Name: "n", Bio: &bio, UpdatedAt: now}
+
+	modified := original
+	modified.Bio = &bio2                    // 指针不同但值相同
+	modified.UpdatedAt = now.In(time.Local) // 时区不同但时间相同
+	q := a.Update().SetChanged(original, &modified)
+	if !q.IsNoop() {
+		t.Errorf("got %s", q)
+	}
+	if _, err := q.Build(); !errors.Is(err, ErrNoChanges) {
+		t.Errorf("got %v", err)
+	}
+
+	modified.ID = 2
+	modified.Name = "m"
+	modified.UpdatedAt = now.Add(time.Second)
+	tests := []struct {
+		query    *UpdateQuery
+		expected string
+		args     []any
+	}{
+		{
+			query:    a.Update().SetChanged(original, modified),
+			expected: "UPDATE `account` SET `name`=?, `updated_at`=? WHERE `id` = ?",
+			args:     []any{"m", now.Add(time.Second), int64(1)},
+		},
+		{
+			query:    a.Update(a.Bio.Assign(nil)).Where(a.TenantID.Eq(Val(3))).SetChanged(original, modified, Exclude(a.Name)),
+			expected: "UPDATE `account` SET `bio`=NULL, `updated_at`=? WHERE `tenant_id` = ? AND `id` = ?",
+			args:     []any{now.Add(time.Second), 3, int64(1)},
+		},
+		{
+			query:    m.Update().SetChanged(membershipRow{UserID: 1, DeptID: 2, Role: "a"}, membershipRow{UserID: 1, DeptID: 2, Role: "b"}), // role 不是表中的列
+			expected: "UPDATE `membership` WHERE `user_id` = ? AND `dept_id` = ?",
+		},
+		{
+			query:    m.Update(m.UserID.Assign(Val(5))).SetChanged(membershipRow{UserID: 1, DeptID: 2}, membershipRow{UserID: 5, DeptID: 2}),
+			expected: "UPDATE `membership` SET `user_id`=? WHERE `user_id` = ? AND `dept_id` = ?",
+			args:     []any{5, 1, 2},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			s, err := test.query.Build()
+			if test.args == nil {
+				if !errors.Is(err, ErrNoChanges) {
+					t.Errorf("got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s.SQL != test.expected {
+				t.Errorf("got %s, want %s", s.SQL, test.expected)
+			}
+			if !reflect.DeepEqual(s.Args, test.args) {
+				t.Errorf("got %v, want %v", s.Args, test.args)
+			}
+		})
+	}
+
+	// 主键条件在生成 SQL 时加入，不会被之后的 Where 覆盖
+	s, err := a.Update().SetChanged(original, modified).Where(a.TenantID.Eq(PH)).Tenant(9).Build(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "UPDATE `account` SET `name`=?, `updated_at`=? WHERE `tenant_id` = ? AND `id` = ?"; s.SQL != expected {
+		t.Errorf("got %s, want %s", s.SQL, expected)
+	}
+
+	// 表没有声明主键（如 sbgen 生成的表没有 ddl tag）或 struct 中没有主键字段时返回错误
+	type noKeyTable struct {
+		Table `db:"account"`
+		ID    Column `db:"id"`
+		Name  Column `db:"name"`
+	}
+	n := New[noKeyTable]("")
+	for _, q := range []*UpdateQuery{n.Update().SetChanged(original, modified), a.Update().SetChanged(membershipRow{}, membershipRow{Role: "a"})} {
+		if _, err := q.Build(); !errors.Is(err, ErrNoPrimaryKey) {
+			t.Errorf("got %v, want %v", err, ErrNoPrimaryKey)
+		}
+		if _, err := q.Compile().Args(); !errors.Is(err, ErrNoPrimaryKey) {
+			t.Errorf("got %v, want %v", err, ErrNoPrimaryKey)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("not panic")
+		}
+	}()
+	a.Update().SetChanged(accountRow{}, accountPatch{})
+}
+
+type membershipRow struct {
+	UserID int    `db:"user_id"`
+	DeptID int    `db:"dept_id"`
+	Role   string `db:"role"`
+}
@@ -7,30 +7,73 @@ import (
 )
 
 type Table struct {
-	name  string
-	alias string
+	schema string // 库名，为空时使用连接的当前库
+	name   string
+	alias  string
+	meta   *TableMeta // 由 New 设置，手动构建时为 nil
 }
 
 func (t Table) isTable() {}
 
+func (t Table) getSchema() string { return t.schema }
+
 func (t Table) getName() string { return t.name }
 
 func (t Table) getAlias() string { return t.alias }
 
+func (t Table) getMeta() *TableMeta { return t.meta }
+
 func (t Table) WriteSQL(buf *bytes.Buffer, aliasMode AliasMode) {
 	if aliasMode != NoAlias {
-		buf.WriteByte('`')
 		if t.alias == "" {
-			buf.WriteString(t.name)
+			writeTableName(buf, t)
 		} else {
+			buf.WriteByte('`')
 			buf.WriteString(t.alias)
+			buf.WriteByte('`')
 		}
-		buf.WriteString("`.*")
+		buf.WriteString(".*")
 	} else {
 		buf.WriteByte('*')
 	}
 }
 
+// Columns 按字段顺序返回表的所有列，可以用于代替 SELECT *：Select(u.Columns()).From(u)
+func (t Table) Columns() Columns {
+	if t.meta == nil {
+		return nil
+	}
+	columns := make(Columns, len(t.meta.Columns))
+	for i, column := range t.meta.Columns {
+		columns[i] = Column{name: column.Name, table: &t}
+	}
+	return columns
+}
+
+// PrefixedColumns 按字段顺序返回表的所有列，每一列命名为 "别名.列名"（没有别名时使用表名），
+// 用于 join 时扫描到以别名为 tag 的 struct 字段：Select(u, d.PrefixedColumns()).FromJoin(...) // `d`.`id` AS `d.id`, ...
+func (t Table) PrefixedColumns() Columns {
+	columns := t.Columns()
+	prefix := t.alias
+	if prefix == "" {
+		prefix = t.name
+	}
+	for i := range columns {
+		columns[i].alias = prefix + "." + columns[i].name
+	}
+	return columns
+}
+
+// Column 按列名查找列
+func (t Table) Column(name string) (Column, bool) {
+	if t.meta != nil {
+		if _, ok := t.meta.Column(name); ok {
+			return Column{name: name, table: &t}, true
+		}
+	}
+	return Column{}, false
+}
+
 func (t Table) Select(expressions ...Expression) *SelectQuery {
 	if len(expressions) == 0 {
 		return Select(t).From(t)
@@ -51,48 +94,123 @@ func (t Table) Delete() *DeleteQuery {
 }
 
 type AnyTable interface {
+	getSchema() string
 	getName() string
 	getAlias() string
+	getMeta() *TableMeta
 	isTable()
 }
 
 var tableType = reflect.TypeOf(Table{})
 
-func New[T AnyTable](alias string) *T {
-	var t T
-	ptr := reflect.ValueOf(&t)  // reflect.Value -> &t
-	rv := reflect.Indirect(ptr) // reflect.value -> t
-	// 这里不直接使用 rv := reflect.ValueOf(t) 的原因是不会设置 flagAddr，导致后面 f0.Set() 时会被认为是 unaddressable
-
-	fieldCount := rv.NumField()
-	if fieldCount == 0 {
-		return &t
-	}
-
-	f0 := rv.Field(0)
-	if f0.Type() != tableType { // 第 0 个字段不是内嵌的 Table，说明不符合规范，不处理
-		return &t
-	}
-
-	rt := reflect.TypeOf(t)
-	name := rt.Field(0).Tag.Get("db")
-	table := Table{name: name, alias: alias}
-	f0.Set(reflect.ValueOf(table))
-
-	if fieldCount > 1 {
-		for i := 1; i < fieldCount; i++ {
-			f := rv.Field(i)
-			if f.Type() == columnType {
-				fi := rt.Field(i)
-				if fi.IsExported() {
-					name := fi.Tag.Get("db")
-					if name == "" {
-						name = strings.ToLower(fi.Name)
-					}
-					f.Set(reflect.ValueOf(Column{name: name, table: &table}))
-				}
-			}
-		}
+// asTable 返回与 table 对应的 *Table，用于生成属于它的列
+func asTable(table AnyTable) *Table {
+	return &Table{schema: table.getSchema(), name: table.getName(), alias: table.getAlias(), meta: table.getMeta()}
+}
+
+// NewOption 是 New 中针对单个表对象的设置
+type NewOption func(*Table)
+
+// WithSchema 设置表对象的库名，覆盖 db tag 中的库名
+func WithSchema(schema string) NewOption {
+	return func(t *Table) {
+		t.schema = schema
+	}
+}
+
+// WithTableName 设置表对象的实际表名，例如分表后的 log_202610，列仍然属于这个表对象
+func WithTableName(name string) NewOption {
+	return func(t *Table) {
+		t.name = name
+	}
+}
+
+// WithTableSuffix 在表名后加上后缀，例如 New[LogTable]("", WithTableSuffix("_202610")) 的表名为 log_202610
+func WithTableSuffix(suffix string) NewOption {
+	return func(t *Table) {
+		t.name += suffix
+	}
+}
+
+// WithTableNameFunc 根据 db tag 中的表名计算实际表名，可以用于按 id 或时间分表
+func WithTableNameFunc(fn func(name string) string) NewOption {
+	return func(t *Table) {
+		t.name = fn(t.name)
+	}
+}
+
+// tableNameTemplate 是模板模式下的表名；输出时先写入 templateMarker，生成语句时再由 sqlString 替换为 %s
+const (
+	tableNameTemplate = "%s"
+	templateMarker    = "\x00"
+)
+
+// sqlString 返回 buf 中的语句，模板模式下会将其他的 % 转义为 %%，使 Column.Mod、LIKE 等输出的 % 不影响 fmt.Sprintf
+func sqlString(buf *bytes.Buffer) string {
+	sql := buf.String()
+	if strings.Contains(sql, templateMarker) {
+		sql = strings.ReplaceAll(sql, "%", "%%")
+		sql = strings.ReplaceAll(sql, templateMarker, tableNameTemplate)
+	}
+	return sql
+}
+
+// WithTableTemplate 使表名（包括库名）输出为 %s，语句中其他的 % 会转义为 %%，
+// 生成的语句可以缓存起来，之后再用 fmt.Sprintf 填入实际的表名：
+//
+//	sql := New[LogTable]("", WithTableTemplate()).Select().Where(...).String() // SELECT * FROM %s WHERE ...
+//	fmt.Sprintf(sql, "`log_202610`")
+func WithTableTemplate() NewOption {
+	return func(t *Table) {
+		t.schema = ""
+		t.name = tableNameTemplate
+	}
+}
+
+// New 创建表对象，字段的反射结果按类型缓存在 TableMeta 中，之后只需要给 Table 和 Column 字段赋值
+func New[T AnyTable](alias string, opts ...NewOption) *T {
+	t := new(T)
+	meta := tableMetaOf(reflect.TypeOf(t).Elem())
+	if meta == nil { // 没有内嵌的 Table，说明不符合规范，不处理
+		return t
+	}
+
+	rv := reflect.ValueOf(t).Elem()
+	table := &Table{schema: meta.Schema, name: meta.Name, alias: alias, meta: meta}
+	for _, opt := range opts {
+		opt(table)
+	}
+	// 通过指针赋值而不是 Set(reflect.ValueOf(...))，避免装箱产生的内存分配
+	*rv.FieldByIndex(meta.tableIndex).Addr().Interface().(*Table) = *table
+	for _, column := range meta.Columns {
+		*rv.FieldByIndex(column.Index).Addr().Interface().(columnField).column() = Column{name: column.Name, table: table}
+	}
+	for _, column := range meta.shadowed {
+		*rv.FieldByIndex(column.Index).Addr().Interface().(columnField).column() = Column{name: column.Name, table: table}
+	}
+	return t
+}
+
+func columnName(field reflect.StructField, naming NamingStrategy) string {
+	name := field.Tag.Get("db")
+	if name == "" {
+		name = naming(field.Name)
+	}
+	return name
+}
+
+// writeTableName 输出带反引号的表名，有库名时输出 `schema`.`name`，模板模式下输出 %s
+func writeTableName(buf *bytes.Buffer, table AnyTable) {
+	if table.getName() == tableNameTemplate {
+		buf.WriteString(templateMarker)
+		return
+	}
+	if schema := table.getSchema(); schema != "" {
+		buf.WriteByte('`')
+		buf.WriteString(schema)
+		buf.WriteString("`.")
 	}
-	return &t
+	buf.WriteByte('`')
+	buf.WriteString(table.getName())
+	buf.WriteByte('`')
 }
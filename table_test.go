@@ -2,6 +2,7 @@ package sb
 
 import (
 	"bytes"
+	"fmt"
 	"testing"
 )
 
@@ -88,3 +89,211 @@ func TestNewTable(t *testing.T) {
 		})
 	}
 }
+
+func BenchmarkNew(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		New[AccountTable]("a")
+	}
+}
+
+func BenchmarkNewParallel(b *testing.B) {
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			New[AccountTable]("a")
+		}
+	})
+}
+
+type AuditColumns struct {
+	CreatedAt Column `db:"created_at" ddl:"type:datetime;not null"`
+	UpdatedAt Column `db:"updated_at" ddl:"type:datetime"`
+}
+
+type tenantColumns struct {
+	TenantID Column `db:"tenant_id" ddl:"type:int;not null;index"`
+	AuditColumns
+}
+
+type MixinTable struct {
+	ID Column `db:"id" ddl:"type:int;primary key"`
+	tenantColumns
+	Table        `db:"mixin"`
+	AuditColumns `sb:"prefix:audit_"`
+	UpdatedAt    Column `db:"updated_at" ddl:"type:timestamp"` // 与 tenantColumns 中的列重名，层级浅的优先
+	Name         Column `ddl:"type:varchar(10)"`
+}
+
+func TestNewEmbedded(t *testing.T) {
+	m := New[MixinTable]("m")
+	if m.name != "mixin" || m.alias != "m" {
+		t.Fatalf("got %+v", m.Table)
+	}
+
+	columns := []struct {
+		column Column
+		name   string
+	}{
+		{m.ID, "id"},
+		{m.TenantID, "tenant_id"},
+		{m.tenantColumns.CreatedAt, "created_at"},
+		{m.CreatedAt, "audit_created_at"},
+		{m.AuditColumns.UpdatedAt, "audit_updated_at"},
+		{m.UpdatedAt, "updated_at"},
+		{m.tenantColumns.UpdatedAt, "updated_at"}, // 被忽略的字段与同名的列相同
+		{m.Name, "name"},
+	}
+	for _, c := range columns {
+		if c.column.name != c.name || c.column.table == nil || *c.column.table != m.Table {
+			t.Errorf("got %+v, want %s", c.column, c.name)
+		}
+	}
+
+	if got := Select(m.Columns()).From(m).String(); got != "SELECT `id`, `tenant_id`, `created_at`, `updated_at`, `audit_created_at`, `audit_updated_at`, `name` FROM `mixin`" {
+		t.Errorf("got %s", got)
+	}
+	if got := CreateTable[MixinTable]().String(); got != "CREATE TABLE `mixin` (`id` int NOT NULL, `tenant_id` int NOT NULL, "+
+		"`created_at` datetime NOT NULL, `updated_at` timestamp, `audit_created_at` datetime NOT NULL, `audit_updated_at` datetime, "+
+		"`name` varchar(10), PRIMARY KEY (`id`), KEY `tenant_id` (`tenant_id`))" {
+		t.Errorf("got %s", got)
+	}
+}
+
+type AuditLogTable struct {
+	Table  `db:"audit.log" ddl:"engine:InnoDB"`
+	ID     Column `db:"id" ddl:"type:int;primary key"`
+	UserID Column `db:"user_id" ddl:"type:int;not null"`
+}
+
+type CoreUserTable struct {
+	Table `db:"core.user"`
+	ID    Column `db:"id"`
+	Name  Column `db:"name"`
+}
+
+func TestTableSchema(t *testing.T) {
+	l := New[AuditLogTable]("")
+	u := New[CoreUserTable]("")
+	u2 := New[CoreUserTable]("u2", WithSchema("core_bak"))
+	other := New[AuditLogTable]("", WithSchema("audit_2023"))
+
+	tests := []struct {
+		query    fmt.Stringer
+		expected string
+	}{
+		{
+			query:    l.Select().Where(l.ID.Eq(PH)),
+			expected: "SELECT * FROM `audit`.`log` WHERE `id` = ?",
+		},
+		{
+			query:    Select(l.ID, u).FromJoin(l.InnerJoin(u, u.ID.Eq(&l.UserID))),
+			expected: "SELECT `audit`.`log`.`id`, `core`.`user`.* FROM `audit`.`log` JOIN `core`.`user` ON `core`.`user`.`id` = `audit`.`log`.`user_id`",
+		},
+		{
+			query:    Select(u2.Name).FromJoin(u2.LeftJoin(other, other.UserID.Eq(&u2.ID))),
+			expected: "SELECT `u2`.`name` FROM `core_bak`.`user` AS `u2` LEFT JOIN `audit_2023`.`log` ON `audit_2023`.`log`.`user_id` = `u2`.`id`",
+		},
+		{
+			query:    u.Insert(u.Name),
+			expected: "INSERT INTO `core`.`user` (`name`) VALUES (?)",
+		},
+		{
+			query:    u.Update(u.Name.Assign(PH)).Where(u.ID.Eq(PH)),
+			expected: "UPDATE `core`.`user` SET `name`=? WHERE `id` = ?",
+		},
+		{
+			query:    u.Delete().Where(u.ID.Eq(PH)),
+			expected: "DELETE `core`.`user` WHERE `id` = ?",
+		},
+		{
+			query:    CreateTable[AuditLogTable](),
+			expected: "CREATE TABLE `audit`.`log` (`id` int NOT NULL, `user_id` int NOT NULL, PRIMARY KEY (`id`)) ENGINE=InnoDB",
+		},
+		{
+			query:    DropTable(l, other),
+			expected: "DROP TABLE `audit`.`log`, `audit_2023`.`log`",
+		},
+		{
+			query:    RenameTable(l, "log_old"),
+			expected: "RENAME TABLE `audit`.`log` TO `audit`.`log_old`",
+		},
+		{
+			query:    AlterTable(l).DropColumn(l.UserID),
+			expected: "ALTER TABLE `audit`.`log` DROP COLUMN `user_id`",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			if got := test.query.String(); got != test.expected {
+				t.Errorf("got %s, want %s", got, test.expected)
+			}
+		})
+	}
+
+	target, err := SchemaOf[AuditLogTable]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if target.Schema != "audit" || target.Name != "log" {
+		t.Errorf("got %+v", target)
+	}
+	current := *target
+	current.Columns = current.Columns[:1]
+	if got := DiffSchema(&current, target).Statements(); len(got) != 1 || got[0] != "ALTER TABLE `audit`.`log` ADD COLUMN `user_id` int NOT NULL AFTER `id`" {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestDynamicTableName(t *testing.T) {
+	month := func(name string) string { return name + "_202611" }
+	tests := []struct {
+		table    *AuditLogTable
+		expected string
+	}{
+		{
+			table:    New[AuditLogTable]("", WithTableName("log_202610")),
+			expected: "SELECT `audit`.`log_202610`.`id` FROM `audit`.`log_202610` JOIN `core`.`user` AS `u` ON `u`.`id` = `audit`.`log_202610`.`user_id`",
+		},
+		{
+			table:    New[AuditLogTable]("", WithSchema("archive"), WithTableSuffix("_202610")),
+			expected: "SELECT `archive`.`log_202610`.`id` FROM `archive`.`log_202610` JOIN `core`.`user` AS `u` ON `u`.`id` = `archive`.`log_202610`.`user_id`",
+		},
+		{
+			table:    New[AuditLogTable]("l", WithTableNameFunc(month)),
+			expected: "SELECT `l`.`id` FROM `audit`.`log_202611` AS `l` JOIN `core`.`user` AS `u` ON `u`.`id` = `l`.`user_id`",
+		},
+		{
+			table:    New[AuditLogTable]("", WithTableTemplate()),
+			expected: "SELECT %s.`id` FROM %s JOIN `core`.`user` AS `u` ON `u`.`id` = %s.`user_id`",
+		},
+	}
+
+	u := New[CoreUserTable]("u")
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			l := test.table
+			if got := Select(l.ID).FromJoin(l.InnerJoin(u, u.ID.Eq(&l.UserID))).String(); got != test.expected {
+				t.Errorf("got %s, want %s", got, test.expected)
+			}
+		})
+	}
+
+	l := New[AuditLogTable]("", WithTableTemplate())
+	sql := l.Select().Where(l.ID.Eq(PH)).LockForUpdate().String()
+	if got := fmt.Sprintf(sql, "`log_202610`"); got != "SELECT * FROM `log_202610` WHERE `id` = ? FOR UPDATE" {
+		t.Errorf("got %s", got)
+	}
+	if got := Select(l.Columns()).From(l).String(); got != "SELECT `id`, `user_id` FROM %s" {
+		t.Errorf("got %s", got)
+	}
+	// 其他的 % 会被转义，不影响 fmt.Sprintf
+	sql = Select(l.ID.Mod(Expr("2")), Expr("`user_id` LIKE '1%'")).From(l).Where(l.UserID.In(Select(u.ID).From(u).Where(u.Name.Eq(Expr("'%a%'"))))).String()
+	if got := fmt.Sprintf(sql, "`log_202610`"); got != "SELECT `id`%2, `user_id` LIKE '1%' FROM `log_202610` WHERE `user_id` IN (SELECT `id` FROM `core`.`user` WHERE `name` = '%a%')" {
+		t.Errorf("got %s", got)
+	}
+	if got := l.Update().Set(l.UserID.Assign(l.UserID.Mod(Expr("10")))).String(); got != "UPDATE %s SET `user_id`=`user_id`%%10" {
+		t.Errorf("got %s", got)
+	}
+}
@@ -0,0 +1,482 @@
+package sb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+var (
+	ErrNoTenant    = errors.New("sb: tenant required")                           // 严格模式下，涉及声明了租户列的表的语句没有设置租户
+	ErrNamedTenant = errors.New("sb: tenant is not supported with named values") // NamedValues 的值由调用方的 struct 提供，无法绑定租户
+)
+
+var tenantStrict atomic.Bool
+
+// SetTenantStrict 开启严格模式后，涉及声明了租户列的表（包括 JOIN 的表和子查询）的语句没有设置租户时，
+// Build、StringE、Compiled.Args 和 FetchPage 返回 ErrNoTenant，String 返回空字符串
+func SetTenantStrict(strict bool) {
+	tenantStrict.Store(strict)
+}
+
+type tenantKey struct{}
+
+// ContextWithTenant 返回带有租户 ID 的 context，配合 TenantFrom 使用
+func ContextWithTenant(ctx context.Context, id any) context.Context {
+	return context.WithValue(ctx, tenantKey{}, id)
+}
+
+// TenantFromContext 返回 ContextWithTenant 设置的租户 ID
+func TenantFromContext(ctx context.Context) (any, bool) {
+	id := ctx.Value(tenantKey{})
+	return id, id != nil
+}
+
+// tenantColumn 返回表的租户列，表没有声明租户列时返回 nil
+func tenantColumn(table AnyTable) *Column {
+	if table == nil {
+		return nil
+	}
+	meta := table.getMeta()
+	if meta == nil || meta.Tenant == "" {
+		return nil
+	}
+	return &Column{name: meta.Tenant, table: asTable(table)}
+}
+
+// tenantCond 返回 `tenant_id` = ?，表没有声明租户列或没有设置租户时返回 nil
+func tenantCond(table AnyTable, tenant *Value) Cond {
+	column := tenantColumn(table)
+	if column == nil || tenant == nil {
+		return nil
+	}
+	return column.Eq(*tenant)
+}
+
+// andTenant 在 where 后追加租户的条件
+func andTenant(where Cond, table AnyTable, tenant *Value) Cond {
+	cond := tenantCond(table, tenant)
+	if cond == nil {
+		return where
+	}
+	return andWhere(where, cond)
+}
+
+// checkTenant 在严格模式下检查 tables 是否都设置了租户
+func checkTenant(tenant *Value, tables ...AnyTable) error {
+	if tenant != nil || !tenantStrict.Load() {
+		return nil
+	}
+	for _, table := range tables {
+		if tenantColumn(table) != nil {
+			return fmt.Errorf("%w: %s", ErrNoTenant, table.getName())
+		}
+	}
+	return nil
+}
+
+// checkSubqueries 检查 e 中的子查询
+func checkSubqueries(e any) error {
+	var err error
+	walkSubqueries(e, func(q *SelectQuery) {
+		if err == nil {
+			err = q.checkOwnTenant()
+		}
+	})
+	return err
+}
+
+// inheritTenant 返回 e 的副本，其中没有设置租户的子查询使用最近一层外层语句的租户，tenant 是 e 外层的租户；
+// 不会修改 e 及其中的子查询，子查询可以在多个语句中复用。没有需要修改的子查询时返回 e 本身和 false
+func inheritTenant(e any, tenant *Value) (any, bool) {
+	switch e := e.(type) {
+	case *SelectQuery:
+		if e == nil {
+			return e, false
+		}
+		s := *e
+		changed := s.tenant == nil && tenant != nil
+		if changed {
+			s.tenant = tenant
+		}
+		if v, ok := inheritExpressions(s.expressions, s.tenant); ok {
+			s.expressions, changed = v, true
+		}
+		if v, ok := inheritTenant(s.from.table, s.tenant); ok {
+			s.from.table, changed = v.(AnyTable), true
+		}
+		var joins []Join
+		for i, join := range e.from.joins {
+			table, tableChanged := inheritTenant(join.table, s.tenant)
+			on, onChanged := inheritTenant(join.on, s.tenant)
+			if !tableChanged && !onChanged {
+				continue
+			}
+			if joins == nil {
+				joins = append([]Join(nil), e.from.joins...)
+			}
+			if tableChanged {
+				joins[i].table = table.(AnyTable)
+			}
+			if onChanged {
+				joins[i].on = on.(Cond)
+			}
+		}
+		if joins != nil {
+			s.from.joins, changed = joins, true
+		}
+		if v, ok := inheritTenant(s.where, s.tenant); ok {
+			s.where, changed = v.(Cond), true
+		}
+		if !changed {
+			return e, false
+		}
+		return &s, true
+	case derivedTable:
+		if v, ok := inheritTenant(e.query, tenant); ok {
+			e.query = v.(*SelectQuery)
+			return e, true
+		}
+	case Condition:
+		lv, lok := inheritTenant(e.lv, tenant)
+		if lok {
+			e.lv = lv.(Expression)
+		}
+		rv, rok := inheritTenant(e.rv, tenant)
+		if rok {
+			e.rv = rv.(Expression)
+		}
+		if lok || rok {
+			return e, true
+		}
+	case Conditions:
+		var conditions []Cond
+		for i, cond := range e.conditions {
+			if v, ok := inheritTenant(cond, tenant); ok {
+				if conditions == nil {
+					conditions = append([]Cond(nil), e.conditions...)
+				}
+				conditions[i] = v.(Cond)
+			}
+		}
+		if conditions != nil {
+			e.conditions = conditions
+			return e, true
+		}
+	case Operation:
+		lv, lok := inheritTenant(e.lv, tenant)
+		if lok {
+			e.lv = lv.(Expression)
+		}
+		rv, rok := inheritTenant(e.rv, tenant)
+		if rok {
+			e.rv = rv.(Expression)
+		}
+		if lok || rok {
+			return e, true
+		}
+	case *Function:
+		if v, ok := inheritExpressions(e.Expressions, tenant); ok {
+			f := *e
+			f.Expressions = v
+			return &f, true
+		}
+	case Expressions:
+		return inheritExpressions(e, tenant)
+	case ConcatExpressions:
+		if v, ok := inheritExpressions(Expressions(e), tenant); ok {
+			return ConcatExpressions(v), true
+		}
+	case Tuple:
+		if v, ok := inheritExpressions(Expressions(e), tenant); ok {
+			return Tuple(v), true
+		}
+	case Assignments:
+		var assignments Assignments
+		for i, assignment := range e {
+			if v, ok := inheritTenant(assignment.value, tenant); ok {
+				if assignments == nil {
+					assignments = append(Assignments(nil), e...)
+				}
+				assignments[i].value = v.(Expression)
+			}
+		}
+		if assignments != nil {
+			return assignments, true
+		}
+	}
+	return e, false
+}
+
+func inheritExpressions(e Expressions, tenant *Value) (Expressions, bool) {
+	var expressions Expressions
+	for i, expression := range e {
+		if v, ok := inheritTenant(expression, tenant); ok {
+			if expressions == nil {
+				expressions = append(Expressions(nil), e...)
+			}
+			expressions[i] = v.(Expression)
+		}
+	}
+	if expressions == nil {
+		return e, false
+	}
+	return expressions, true
+}
+
+// walkSubqueries 对 e 中的每个 SelectQuery 调用 fn，包括 e 本身和子查询中的子查询
+func walkSubqueries(e any, fn func(*SelectQuery)) {
+	switch e := e.(type) {
+	case *SelectQuery:
+		if e == nil {
+			return
+		}
+		fn(e)
+		walkSubqueries(e.expressions, fn)
+		walkSubqueries(e.from.table, fn)
+		for _, join := range e.from.joins {
+			walkSubqueries(join.table, fn)
+			walkSubqueries(join.on, fn)
+		}
+		walkSubqueries(e.where, fn)
+	case derivedTable:
+		walkSubqueries(e.query, fn)
+	case Condition:
+		walkSubqueries(e.lv, fn)
+		walkSubqueries(e.rv, fn)
+	case Conditions:
+		for _, cond := range e.conditions {
+			walkSubqueries(cond, fn)
+		}
+	case Operation:
+		walkSubqueries(e.lv, fn)
+		walkSubqueries(e.rv, fn)
+	case *Function:
+		walkSubqueries(e.Expressions, fn)
+	case Expressions:
+		for _, expression := range e {
+			walkSubqueries(expression, fn)
+		}
+	case ConcatExpressions:
+		walkSubqueries(Expressions(e), fn)
+	case Tuple:
+		walkSubqueries(Expressions(e), fn)
+	case Assignments:
+		for _, assignment := range e {
+			walkSubqueries(assignment.value, fn)
+		}
+	}
+}
+
+// Tenant 设置租户：FROM 中的表追加 WHERE 条件 `tenant_id` = ?，JOIN 的表按 FromTables.scopes 的规则追加在 ON 或 WHERE 中，
+// 没有设置租户的子查询在生成语句时使用它，子查询本身不会被修改
+func (q *SelectQuery) Tenant(id any) *SelectQuery {
+	v := Val(id)
+	q.tenant = &v
+	return q
+}
+
+// TenantFrom 使用 ContextWithTenant 设置的租户，context 中没有租户时不做修改
+func (q *SelectQuery) TenantFrom(ctx context.Context) *SelectQuery {
+	if id, ok := TenantFromContext(ctx); ok {
+		return q.Tenant(id)
+	}
+	return q
+}
+
+// checkOwnTenant 检查 q 自身的租户以及 JOIN 的条件能否正确放置，不检查子查询
+func (q *SelectQuery) checkOwnTenant() error {
+	if _, _, err := q.from.scopes(q.deleted, q.tenant); err != nil {
+		return err
+	}
+	tables := make([]AnyTable, 0, len(q.from.joins)+1)
+	tables = append(tables, q.from.table)
+	for _, join := range q.from.joins {
+		tables = append(tables, join.table)
+	}
+	return checkTenant(q.tenant, tables...)
+}
+
+func (q *SelectQuery) checkTenant() error {
+	return checkSubqueries(q.scoped())
+}
+
+// scoped 返回子查询继承了租户的语句，不修改 q
+func (q *SelectQuery) scoped() *SelectQuery {
+	if s, ok := inheritTenant(q, nil); ok {
+		return s.(*SelectQuery)
+	}
+	return q
+}
+
+// Tenant 设置租户，追加 WHERE 条件 `tenant_id` = ?，子查询的规则与 SelectQuery.Tenant 相同
+func (q *UpdateQuery) Tenant(id any) *UpdateQuery {
+	v := Val(id)
+	q.tenant = &v
+	return q
+}
+
+// TenantFrom 使用 ContextWithTenant 设置的租户，context 中没有租户时不做修改
+func (q *UpdateQuery) TenantFrom(ctx context.Context) *UpdateQuery {
+	if id, ok := TenantFromContext(ctx); ok {
+		return q.Tenant(id)
+	}
+	return q
+}
+
+func (q *UpdateQuery) checkTenant() error {
+	if err := checkTenant(q.tenant, q.table); err != nil {
+		return err
+	}
+	q = q.scoped()
+	if err := checkSubqueries(q.assignments); err != nil {
+		return err
+	}
+	return checkSubqueries(q.where)
+}
+
+// Tenant 设置租户，追加 WHERE 条件 `tenant_id` = ?，子查询的规则与 SelectQuery.Tenant 相同
+func (q *DeleteQuery) Tenant(id any) *DeleteQuery {
+	v := Val(id)
+	q.tenant = &v
+	return q
+}
+
+// TenantFrom 使用 ContextWithTenant 设置的租户，context 中没有租户时不做修改
+func (q *DeleteQuery) TenantFrom(ctx context.Context) *DeleteQuery {
+	if id, ok := TenantFromContext(ctx); ok {
+		return q.Tenant(id)
+	}
+	return q
+}
+
+// scoped 返回子查询继承了租户的语句，不修改 q
+func (q *UpdateQuery) scoped() *UpdateQuery {
+	assignments, assignmentsChanged := inheritTenant(q.assignments, q.tenant)
+	where, whereChanged := inheritTenant(q.where, q.tenant)
+	if !assignmentsChanged && !whereChanged {
+		return q
+	}
+	s := *q
+	if assignmentsChanged {
+		s.assignments = assignments.(Assignments)
+	}
+	if whereChanged {
+		s.where = where.(Cond)
+	}
+	return &s
+}
+
+func (q *DeleteQuery) checkTenant() error {
+	if err := checkTenant(q.tenant, q.table); err != nil {
+		return err
+	}
+	q = q.scoped()
+	return checkSubqueries(q.where)
+}
+
+// scoped 返回子查询继承了租户的语句，不修改 q
+func (q *DeleteQuery) scoped() *DeleteQuery {
+	where, ok := inheritTenant(q.where, q.tenant)
+	if !ok {
+		return q
+	}
+	s := *q
+	s.where = where.(Cond)
+	return &s
+}
+
+// Tenant 设置租户，插入的列中没有租户列时会自动追加租户列和对应的值；
+// INSERT ... SELECT 时 SELECT 也会使用该租户
+func (q *InsertQuery) Tenant(id any) *InsertQuery {
+	v := Val(id)
+	q.tenant = &v
+	return q
+}
+
+// TenantFrom 使用 ContextWithTenant 设置的租户，context 中没有租户时不做修改
+func (q *InsertQuery) TenantFrom(ctx context.Context) *InsertQuery {
+	if id, ok := TenantFromContext(ctx); ok {
+		return q.Tenant(id)
+	}
+	return q
+}
+
+func (q *InsertQuery) checkTenant() error {
+	if err := checkTenant(q.tenant, q.table); err != nil {
+		return err
+	}
+	if q.tenant != nil && q.named() && tenantColumn(q.table) != nil {
+		return ErrNamedTenant
+	}
+	q = q.scoped()
+	if q.selectQuery != nil {
+		if err := q.selectQuery.checkTenant(); err != nil {
+			return err
+		}
+	}
+	return checkSubqueries(q.values)
+}
+
+// scoped 返回子查询继承了租户、并追加了租户列和值的语句，不修改 q，无需修改时返回 q
+func (q *InsertQuery) scoped() *InsertQuery {
+	s := *q
+	changed := false
+	if v, ok := inheritTenant(q.selectQuery, q.tenant); ok {
+		s.selectQuery, changed = v.(*SelectQuery), true
+	}
+	if v, ok := inheritExpressions(q.values, q.tenant); ok {
+		s.values, changed = v, true
+	}
+	var rows []Expressions
+	for i, row := range q.rows {
+		if v, ok := inheritExpressions(row, q.tenant); ok {
+			if rows == nil {
+				rows = append([]Expressions(nil), q.rows...)
+			}
+			rows[i] = v
+		}
+	}
+	if rows != nil {
+		s.rows, changed = rows, true
+	}
+
+	column := tenantColumn(q.table)
+	hasColumn := column == nil || q.tenant == nil || q.named() // NamedValues 由 checkTenant 返回 ErrNamedTenant
+	for _, c := range q.columns {
+		if column != nil && c.name == column.name {
+			hasColumn = true
+		}
+	}
+	if hasColumn { // 无需追加租户列
+		if !changed {
+			return q
+		}
+		return &s
+	}
+
+	s.columns = append(q.columns[:len(q.columns):len(q.columns)], *column)
+	value := *q.tenant
+	switch {
+	case s.rows != nil:
+		rows := make([]Expressions, len(s.rows))
+		for i, row := range s.rows {
+			rows[i] = append(row[:len(row):len(row)], value)
+		}
+		s.rows = rows
+	case s.selectQuery != nil:
+		selectQuery := s.selectQuery.Copy()
+		selectQuery.expressions = append(s.selectQuery.expressions[:len(s.selectQuery.expressions):len(s.selectQuery.expressions)], value)
+		s.selectQuery = selectQuery
+	case s.values != nil:
+		s.values = append(s.values[:len(s.values):len(s.values)], value)
+	default: // 自动填充的 '?'
+		s.values = make(Expressions, len(q.columns), len(q.columns)+1)
+		for i := range s.values {
+			s.values[i] = PH
+		}
+		s.values = append(s.values, value)
+	}
+	return &s
+}
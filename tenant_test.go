@@ -0,0 +1,384 @@
+package sb
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type OrderTable struct {
+	Table    `db:"order"`
+	ID       Column `db:"id"`
+	TenantID Column `db:"tenant_id" sb:"tenant"`
+	UserID   Column `db:"user_id"`
+	Amount   Column `db:"amount"`
+}
+
+type InvoiceTable struct {
+	Table   `db:"invoice"`
+	ID      Column `db:"id"`
+	OrderID Column `db:"order_id"`
+	Org     Column `db:"org_id"`
+}
+
+type orderRow struct {
+	ID     int64 `db:"id"`
+	UserID int64 `db:"user_id"`
+	Amount int   `db:"amount"`
+}
+
+func TestTenant(t *testing.T) {
+	RegisterTable[InvoiceTable](WithTenantColumn("org_id"))
+	o := New[OrderTable]("o")
+	i := New[InvoiceTable]("i")
+	u := New[UserTable]("u")
+	p := New[PostTable]("p")
+
+	tests := []struct {
+		query interface {
+			Build(...any) (Statement, error)
+		}
+		bind     []any // Build 的参数
+		expected string
+		args     []any
+	}{
+		{
+			query:    o.Select().Where(o.ID.Eq(Val(1))).Tenant(7),
+			expected: "SELECT * FROM `order` WHERE `id` = ? AND `tenant_id` = ?",
+			args:     []any{1, 7},
+		},
+		{
+			query:    o.Select().Tenant(7),
+			expected: "SELECT * FROM `order` WHERE `tenant_id` = ?",
+			args:     []any{7},
+		},
+		{
+			query:    Select(o.ID, i.ID).FromJoin(o.LeftJoin(i, i.OrderID.Eq(&o.ID))).Where(o.Amount.Gt(Val(10))).Tenant(7),
+			expected: "SELECT `o`.`id`, `i`.`id` FROM `order` AS `o` LEFT JOIN `invoice` AS `i` ON `i`.`order_id` = `o`.`id` AND `i`.`org_id` = ? WHERE `o`.`amount` > ? AND `o`.`tenant_id` = ?",
+			args:     []any{7, 10, 7},
+		},
+		{
+			// 子查询继承外层的租户
+			query:    u.Select().Where(u.ID.In(Select(o.UserID).From(o))).Tenant(7),
+			expected: "SELECT * FROM `user` WHERE `id` IN (SELECT `user_id` FROM `order` WHERE `tenant_id` = ?)",
+			args:     []any{7},
+		},
+		{
+			query:    Select(Expr("*")).FromSelect(o.Select(), "t").Tenant(7),
+			expected: "SELECT * FROM (SELECT * FROM `order` WHERE `tenant_id` = ?) AS `t`",
+			args:     []any{7},
+		},
+		{
+			// 与软删除一起使用
+			query:    Select(p.Title).FromJoin(p.InnerJoin(o, o.UserID.Eq(&p.AuthorID))).Tenant(7),
+			expected: "SELECT `p`.`title` FROM `post` AS `p` JOIN `order` AS `o` ON `o`.`user_id` = `p`.`author_id` AND `o`.`tenant_id` = ? WHERE `p`.`deleted_at` IS NULL",
+			args:     []any{7},
+		},
+		{
+			query:    o.Update(o.Amount.Assign(Val(1))).Where(o.ID.Eq(Val(2))).Tenant(7),
+			expected: "UPDATE `order` SET `amount`=? WHERE `id` = ? AND `tenant_id` = ?",
+			args:     []any{1, 2, 7},
+		},
+		{
+			query:    o.Delete().Where(o.ID.Eq(Val(2))).Tenant(7),
+			expected: "DELETE `order` WHERE `id` = ? AND `tenant_id` = ?",
+			args:     []any{2, 7},
+		},
+		{
+			query:    o.Insert(o.UserID, o.Amount).Values(Val(1), Val(2)).Tenant(7),
+			expected: "INSERT INTO `order` (`user_id`, `amount`, `tenant_id`) VALUES (?, ?, ?)",
+			args:     []any{1, 2, 7},
+		},
+		{
+			query:    o.Insert(o.UserID, o.Amount).Tenant(7),
+			bind:     []any{1, 2},
+			expected: "INSERT INTO `order` (`user_id`, `amount`, `tenant_id`) VALUES (?, ?, ?)",
+			args:     []any{1, 2, 7},
+		},
+		{
+			// 已经指定了租户列时不再追加
+			query:    o.Insert(o.TenantID, o.UserID).Values(Val(8), Val(1)).Tenant(7),
+			expected: "INSERT INTO `order` (`tenant_id`, `user_id`) VALUES (?, ?)",
+			args:     []any{8, 1},
+		},
+		{
+			query:    o.Insert().FromStructs([]orderRow{{UserID: 1, Amount: 2}, {UserID: 3, Amount: 4}}, Exclude(o.ID)).Tenant(7),
+			expected: "INSERT INTO `order` (`user_id`, `amount`, `tenant_id`) VALUES (?, ?, ?), (?, ?, ?)",
+			args:     []any{int64(1), 2, 7, int64(3), 4, 7},
+		},
+		{
+			query:    o.Insert(o.UserID).Select(u, u.ID).Tenant(7),
+			expected: "INSERT INTO `order` (`user_id`, `tenant_id`) SELECT `id`, ? FROM `user`",
+			args:     []any{7},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			s, err := test.query.Build(test.bind...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s.SQL != test.expected {
+				t.Errorf("got %s, want %s", s.SQL, test.expected)
+			}
+			if !reflect.DeepEqual(s.Args, test.args) {
+				t.Errorf("got %v, want %v", s.Args, test.args)
+			}
+		})
+	}
+}
+
+func TestTenantJoin(t *testing.T) {
+	RegisterTable[InvoiceTable](WithTenantColumn("org_id"))
+	o := New[OrderTable]("o")
+	o2 := New[OrderTable]("o2")
+	i := New[InvoiceTable]("i")
+
+	// 条件只能放在不会补 NULL 的一侧
+	tests := []struct {
+		from     FromTables
+		expected string
+		args     []any
+	}{
+		{
+			from:     o.InnerJoin(i, i.OrderID.Eq(&o.ID)),
+			expected: "FROM `order` AS `o` JOIN `invoice` AS `i` ON `i`.`order_id` = `o`.`id` AND `i`.`org_id` = ? WHERE `o`.`tenant_id` = ?",
+			args:     []any{7, 7},
+		},
+		{
+			from:     o.LeftJoin(i, i.OrderID.Eq(&o.ID)),
+			expected: "FROM `order` AS `o` LEFT JOIN `invoice` AS `i` ON `i`.`order_id` = `o`.`id` AND `i`.`org_id` = ? WHERE `o`.`tenant_id` = ?",
+			args:     []any{7, 7},
+		},
+		{
+			from:     o.RightJoin(i, i.OrderID.Eq(&o.ID)),
+			expected: "FROM `order` AS `o` RIGHT JOIN `invoice` AS `i` ON `i`.`order_id` = `o`.`id` AND `o`.`tenant_id` = ? WHERE `i`.`org_id` = ?",
+			args:     []any{7, 7},
+		},
+		{
+			from:     o.StraightJoin(i, i.OrderID.Eq(&o.ID)),
+			expected: "FROM `order` AS `o` STRAIGHT_JOIN `invoice` AS `i` ON `i`.`order_id` = `o`.`id` AND `i`.`org_id` = ? WHERE `o`.`tenant_id` = ?",
+			args:     []any{7, 7},
+		},
+		{
+			from:     o.CrossJoin(i),
+			expected: "FROM `order` AS `o` CROSS JOIN `invoice` AS `i` ON `i`.`org_id` = ? WHERE `o`.`tenant_id` = ?",
+			args:     []any{7, 7},
+		},
+		{
+			from:     o.NaturalJoin(o2),
+			expected: "FROM `order` AS `o` NATURAL JOIN `order` AS `o2` WHERE `o`.`tenant_id` = ? AND `o2`.`tenant_id` = ?",
+			args:     []any{7, 7},
+		},
+		{
+			from:     o.InnerJoinUsing(o2, o2.UserID),
+			expected: "FROM `order` AS `o` JOIN `order` AS `o2` USING (`user_id`) WHERE `o`.`tenant_id` = ? AND `o2`.`tenant_id` = ?",
+			args:     []any{7, 7},
+		},
+		{
+			// 每个表的条件放在之后第一个 RIGHT JOIN 的 ON 中
+			from: o.RightJoin(i, i.OrderID.Eq(&o.ID)).RightJoin(o2, o2.ID.Eq(&i.OrderID)),
+			expected: "FROM `order` AS `o` RIGHT JOIN `invoice` AS `i` ON `i`.`order_id` = `o`.`id` AND `o`.`tenant_id` = ? " +
+				"RIGHT JOIN `order` AS `o2` ON `o2`.`id` = `i`.`order_id` AND `i`.`org_id` = ? WHERE `o2`.`tenant_id` = ?",
+			args: []any{7, 7, 7},
+		},
+		{
+			from: o.LeftJoin(i, i.OrderID.Eq(&o.ID)).RightJoin(o2, o2.UserID.Eq(&o.UserID)),
+			expected: "FROM `order` AS `o` LEFT JOIN `invoice` AS `i` ON `i`.`order_id` = `o`.`id` AND `i`.`org_id` = ? " +
+				"RIGHT JOIN `order` AS `o2` ON `o2`.`user_id` = `o`.`user_id` AND `o`.`tenant_id` = ? WHERE `o2`.`tenant_id` = ?",
+			args: []any{7, 7, 7},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			s, err := Select(o.ID).FromJoin(test.from).Tenant(7).Build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if expected := "SELECT `o`.`id` " + test.expected; s.SQL != expected {
+				t.Errorf("got %s, want %s", s.SQL, expected)
+			}
+			if !reflect.DeepEqual(s.Args, test.args) {
+				t.Errorf("got %v, want %v", s.Args, test.args)
+			}
+		})
+	}
+}
+
+func TestTenantNamedInsert(t *testing.T) {
+	o := New[OrderTable]("o")
+	u := New[UserTable]("u")
+
+	// NamedValues 的值由调用方的 struct 提供，无法绑定租户
+	for i, q := range []*InsertQuery{
+		o.Insert(o.ID, o.UserID).NamedValues().Tenant(5),
+		o.Insert(o.ID, o.UserID).NamedValues(Expr(":id"), Expr(":user_id")).Tenant(5),
+		o.Insert(o.TenantID, o.UserID).NamedValues().Tenant(5),
+	} {
+		if _, err := q.Build(); !errors.Is(err, ErrNamedTenant) {
+			t.Errorf("%d: Build got %v", i, err)
+		}
+		if _, err := q.StringE(); !errors.Is(err, ErrNamedTenant) {
+			t.Errorf("%d: StringE got %v", i, err)
+		}
+		if _, err := q.Compile().Args(); !errors.Is(err, ErrNamedTenant) {
+			t.Errorf("%d: Compile got %v", i, err)
+		}
+	}
+
+	// 没有租户列的表不受影响
+	s, err := u.Insert(u.ID, u.Name).NamedValues().Tenant(5).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "INSERT INTO `user` (`id`, `name`) VALUES (:id, :name)"; s.SQL != expected || len(s.Args) != 0 {
+		t.Errorf("got %s %v, want %s", s.SQL, s.Args, expected)
+	}
+}
+
+func TestTenantSubqueryReuse(t *testing.T) {
+	o := New[OrderTable]("o")
+	u := New[UserTable]("u")
+	sub := Select(o.UserID).From(o)
+	origin := sub.String()
+
+	tests := []struct {
+		query interface {
+			Build(...any) (Statement, error)
+		}
+		expected string
+		args     []any
+	}{
+		{
+			query:    u.Select().Where(u.ID.In(sub)).Tenant(1),
+			expected: "SELECT * FROM `user` WHERE `id` IN (SELECT `user_id` FROM `order` WHERE `tenant_id` = ?)",
+			args:     []any{1},
+		},
+		{
+			query:    u.Select().Where(u.ID.In(sub)),
+			expected: "SELECT * FROM `user` WHERE `id` IN (SELECT `user_id` FROM `order`)",
+		},
+		{
+			query:    u.Update(u.Name.Assign(Val("a"))).Where(u.ID.In(sub)).Tenant(2),
+			expected: "UPDATE `user` SET `name`=? WHERE `id` IN (SELECT `user_id` FROM `order` WHERE `tenant_id` = ?)",
+			args:     []any{"a", 2},
+		},
+		{
+			query:    u.Delete().Where(u.ID.In(sub)).Tenant(3),
+			expected: "DELETE `user` WHERE `id` IN (SELECT `user_id` FROM `order` WHERE `tenant_id` = ?)",
+			args:     []any{3},
+		},
+		{
+			query:    o.Insert(o.UserID).Values(Func("", sub)).Tenant(4),
+			expected: "INSERT INTO `order` (`user_id`, `tenant_id`) VALUES ((SELECT `user_id` FROM `order` WHERE `tenant_id` = ?), ?)",
+			args:     []any{4, 4},
+		},
+		{
+			// 子查询使用最近一层设置了租户的外层语句的租户
+			query:    u.Select().Where(u.ID.In(Select(o.UserID).From(o).Where(o.ID.In(sub)).Tenant(5))).Tenant(6),
+			expected: "SELECT * FROM `user` WHERE `id` IN (SELECT `user_id` FROM `order` WHERE `id` IN (SELECT `user_id` FROM `order` WHERE `tenant_id` = ?) AND `tenant_id` = ?)",
+			args:     []any{5, 5},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			s, err := test.query.Build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s.SQL != test.expected {
+				t.Errorf("got %s, want %s", s.SQL, test.expected)
+			}
+			if !reflect.DeepEqual(s.Args, test.args) {
+				t.Errorf("got %v, want %v", s.Args, test.args)
+			}
+			if got := sub.String(); got != origin {
+				t.Errorf("subquery modified: got %s, want %s", got, origin)
+			}
+		})
+	}
+}
+
+func TestTenantStrict(t *testing.T) {
+	SetTenantStrict(true)
+	defer SetTenantStrict(false)
+	o := New[OrderTable]("o")
+	u := New[UserTable]("u")
+
+	tests := []struct {
+		query interface {
+			Build(...any) (Statement, error)
+			StringE() (string, error)
+			String() string
+		}
+		err bool
+	}{
+		{query: o.Select(), err: true},
+		{query: o.Select().Tenant(7)},
+		{query: u.Select()},
+		{query: Select(u.ID).FromJoin(u.InnerJoin(o, o.UserID.Eq(&u.ID))), err: true},
+		{query: u.Select().Where(u.ID.In(Select(o.UserID).From(o))), err: true},
+		{query: Select(Expr("*")).FromSelect(o.Select(), "t"), err: true},
+		{query: o.Update(o.Amount.Assign(Val(1))), err: true},
+		{query: o.Delete(), err: true},
+		{query: o.Delete().Tenant(7)},
+		{query: o.Insert(o.UserID), err: true},
+		{query: u.Update(u.Name.Assign(Val("a"))).Where(u.ID.In(Select(o.UserID).From(o))), err: true},
+	}
+
+	for i, test := range tests {
+		_, err := test.query.Build()
+		if test.err != errors.Is(err, ErrNoTenant) {
+			t.Errorf("%d: got %v", i, err)
+		}
+		sql, err := test.query.StringE()
+		if test.err != errors.Is(err, ErrNoTenant) {
+			t.Errorf("%d: got %v", i, err)
+		}
+		if got := test.query.String(); got != sql || test.err != (got == "") {
+			t.Errorf("%d: got %q", i, got)
+		}
+	}
+
+	if _, err := o.Select().Compile().Args(); !errors.Is(err, ErrNoTenant) {
+		t.Errorf("got %v, want ErrNoTenant", err)
+	}
+}
+
+func TestTenantContext(t *testing.T) {
+	o := New[OrderTable]("o")
+	ctx := ContextWithTenant(context.Background(), 7)
+	if id, ok := TenantFromContext(ctx); !ok || id != 7 {
+		t.Fatalf("got %v %v", id, ok)
+	}
+	if _, ok := TenantFromContext(context.Background()); ok {
+		t.Fatal("unexpected tenant")
+	}
+	if got := o.Select().TenantFrom(context.Background()).String(); got != "SELECT * FROM `order`" {
+		t.Errorf("got %s", got)
+	}
+
+	fake := newFakeDB()
+	db := fake.open()
+	defer db.Close()
+	p := o.Select().Where(o.Amount.Gt(PH)).TenantFrom(ctx).Paginate(1, 10)
+	fake.set(p.Count.String(), fakeResult{columns: []string{"COUNT(*)"}, rows: [][]driver.Value{{int64(1)}}})
+	fake.set(p.Query.String(), fakeResult{columns: []string{"id", "user_id", "amount"}, rows: [][]driver.Value{{int64(1), int64(2), int64(3)}}})
+	items, total, err := FetchPage[orderRow](ctx, db, p, StrictScan, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 || len(items) != 1 || items[0].Amount != 3 {
+		t.Errorf("got %v %d", items, total)
+	}
+	for _, call := range fake.executed {
+		if !reflect.DeepEqual(call.args, []driver.Value{int64(5), int64(7)}) {
+			t.Errorf("got %v", call.args)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package sb
+
+import (
+	"bytes"
+	"reflect"
+)
+
+// TypedColumn 是带有值类型的列，比较和赋值的方法直接接受 T 类型的值，值会作为绑定参数输出为 ?：
+//
+//	type UserTable struct {
+//		Table `db:"user"`
+//		ID    TypedColumn[int64]  `db:"id"`
+//		Name  TypedColumn[string] `db:"name"`
+//	}
+//
+//	u.ID.Eq(1)           // `id` = ?，参数为 1
+//	u.ID.Eq("1")         // 编译错误
+//	u.ID.EqExpr(expr)    // 与表达式或其他列比较
+//
+// 需要 Column 的地方（如 Insert、GroupBy）使用 u.ID.Column
+type TypedColumn[T any] struct {
+	Column
+}
+
+// columnField 由 Column 和 TypedColumn 的指针实现，New 通过它设置列
+type columnField interface {
+	column() *Column
+}
+
+func (c *Column) column() *Column { return c }
+
+var columnFieldType = reflect.TypeOf((*columnField)(nil)).Elem()
+
+// isColumnType 判断字段是否为 Column 或 TypedColumn
+func isColumnType(rt reflect.Type) bool {
+	if rt == columnType {
+		return true
+	}
+	// TypedColumn 只有一个内嵌的 Column 字段
+	return rt.Kind() == reflect.Struct && rt.NumField() == 1 && rt.Field(0).Anonymous && rt.Field(0).Type == columnType &&
+		reflect.PointerTo(rt).Implements(columnFieldType)
+}
+
+func (c *TypedColumn[T]) As(alias string) *TypedColumn[T] {
+	c.alias = alias
+	return c
+}
+
+func (c TypedColumn[T]) WriteSQL(buf *bytes.Buffer, aliasMode AliasMode) {
+	c.Column.WriteSQL(buf, aliasMode)
+}
+
+func (c *TypedColumn[T]) Eq(v T) Condition {
+	return Condition{op: "=", lv: &c.Column, rv: Val(v)}
+}
+
+func (c *TypedColumn[T]) Ne(v T) Condition {
+	return Condition{op: "!=", lv: &c.Column, rv: Val(v)}
+}
+
+func (c *TypedColumn[T]) Gt(v T) Condition {
+	return Condition{op: ">", lv: &c.Column, rv: Val(v)}
+}
+
+func (c *TypedColumn[T]) Ge(v T) Condition {
+	return Condition{op: ">=", lv: &c.Column, rv: Val(v)}
+}
+
+func (c *TypedColumn[T]) Lt(v T) Condition {
+	return Condition{op: "<", lv: &c.Column, rv: Val(v)}
+}
+
+func (c *TypedColumn[T]) Le(v T) Condition {
+	return Condition{op: "<=", lv: &c.Column, rv: Val(v)}
+}
+
+// In 输出为 `col` IN (?, ?, ...)，values 为空时输出 `col` IN (NULL)，结果总是为假
+func (c *TypedColumn[T]) In(values ...T) Condition {
+	return Condition{op: "IN", lv: &c.Column, rv: valueList(values)}
+}
+
+// NotIn 输出为 `col` NOT IN (?, ?, ...)，values 为空时输出 1 = 1，结果总是为真
+func (c *TypedColumn[T]) NotIn(values ...T) Condition {
+	if len(values) == 0 {
+		return Condition{op: "=", lv: Expr("1"), rv: Expr("1")}
+	}
+	return Condition{op: "NOT IN", lv: &c.Column, rv: valueList(values)}
+}
+
+// EqColumn 与同类型的列比较，常用于 JOIN 的条件
+func (c *TypedColumn[T]) EqColumn(other *TypedColumn[T]) Condition {
+	return Condition{op: "=", lv: &c.Column, rv: &other.Column}
+}
+
+// EqExpr 等与表达式比较，如子查询、函数或 PH，等同于 c.Column.Eq(e)
+func (c *TypedColumn[T]) EqExpr(e Expression) Condition {
+	return c.Column.Eq(e)
+}
+
+func (c *TypedColumn[T]) NeExpr(e Expression) Condition {
+	return c.Column.Ne(e)
+}
+
+func (c *TypedColumn[T]) GtExpr(e Expression) Condition {
+	return c.Column.Gt(e)
+}
+
+func (c *TypedColumn[T]) GeExpr(e Expression) Condition {
+	return c.Column.Ge(e)
+}
+
+func (c *TypedColumn[T]) LtExpr(e Expression) Condition {
+	return c.Column.Lt(e)
+}
+
+func (c *TypedColumn[T]) LeExpr(e Expression) Condition {
+	return c.Column.Le(e)
+}
+
+func (c *TypedColumn[T]) InExpr(e Expression) Condition {
+	return c.Column.In(e)
+}
+
+func (c *TypedColumn[T]) NotInExpr(e Expression) Condition {
+	return c.Column.NotIn(e)
+}
+
+// AssignExpr 赋值为表达式，如 c.AssignExpr(c.Plus(1))
+func (c *TypedColumn[T]) AssignExpr(e Expression) Assignment {
+	return c.Column.Assign(e)
+}
+
+func (c *TypedColumn[T]) Assign(v T) Assignment {
+	return c.Column.Assign(Val(v))
+}
+
+func (c *TypedColumn[T]) Plus(v T) Operation {
+	return Operation{op: "+", lv: &c.Column, rv: Val(v)}
+}
+
+func (c *TypedColumn[T]) Minus(v T) Operation {
+	return Operation{op: "-", lv: &c.Column, rv: Val(v)}
+}
+
+func valueList[T any](values []T) Expressions {
+	if len(values) == 0 {
+		return Expressions{Expr("NULL")}
+	}
+	list := make(Expressions, len(values))
+	for i, v := range values {
+		list[i] = Val(v)
+	}
+	return list
+}
@@ -0,0 +1,95 @@
+package sb
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type TypedUserTable struct {
+	Table     `db:"typed_user"`
+	ID        TypedColumn[int64]  `db:"id"`
+	Name      TypedColumn[string] `db:"name"`
+	CreatedAt TypedColumn[time.Time]
+	Note      Column
+}
+
+type TypedDeptUserTable struct {
+	Table  `db:"typed_dept_user"`
+	UserID TypedColumn[int64] `db:"user_id"`
+	DeptID TypedColumn[int64] `db:"dept_id"`
+}
+
+func TestTypedColumn(t *testing.T) {
+	u := New[TypedUserTable]("u")
+	du := New[TypedDeptUserTable]("du")
+	if u.ID.name != "id" || u.CreatedAt.name != "createdat" || u.Note.name != "note" || *u.Name.table != u.Table {
+		t.Fatalf("got %+v", u)
+	}
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		query interface {
+			Build(...any) (Statement, error)
+		}
+		expected string
+		args     []any
+	}{
+		{
+			query:    u.Select().Where(u.ID.Eq(1).And(u.Name.Ne("a")).And(u.CreatedAt.Ge(now))),
+			expected: "SELECT * FROM `typed_user` WHERE `id` = ? AND `name` != ? AND `createdat` >= ?",
+			args:     []any{int64(1), "a", now},
+		},
+		{
+			query:    u.Select().Where(u.ID.Gt(1).And(u.ID.Lt(10)).And(u.ID.Le(9)).And(u.Name.In("a", "b")).And(u.Name.NotIn()).And(u.Name.NotIn("c"))),
+			expected: "SELECT * FROM `typed_user` WHERE `id` > ? AND `id` < ? AND `id` <= ? AND `name` IN (?, ?) AND 1 = 1 AND `name` NOT IN (?)",
+			args:     []any{int64(1), int64(10), int64(9), "a", "b", "c"},
+		},
+		{
+			query:    Select(u.Name).FromJoin(u.InnerJoin(du, du.UserID.EqColumn(&u.ID))).Where(du.DeptID.Eq(3).And(u.Note.Eq(PH))),
+			expected: "SELECT `u`.`name` FROM `typed_user` AS `u` JOIN `typed_dept_user` AS `du` ON `du`.`user_id` = `u`.`id` WHERE `du`.`dept_id` = ? AND `u`.`note` = ?",
+			args:     []any{int64(3), "x"},
+		},
+		{
+			query:    u.Update(u.Name.Assign("b"), u.ID.Column.Assign(u.ID.Plus(1))).Where(u.ID.Column.Eq(u.ID.Minus(2))),
+			expected: "UPDATE `typed_user` SET `name`=?, `id`=`id`+? WHERE `id` = `id`-?",
+			args:     []any{"b", int64(1), int64(2)},
+		},
+		{
+			query: u.Update(u.ID.AssignExpr(u.ID.Plus(1))).Where(u.ID.EqExpr(Val(int64(5))).And(u.ID.NeExpr(Func("ABS", u.ID.Minus(2)))).
+				And(u.ID.GtExpr(Val(0))).And(u.ID.GeExpr(Expr("1"))).And(u.ID.LtExpr(Expr("9"))).And(u.ID.LeExpr(Expr("8"))).
+				And(u.ID.InExpr(Select(du.UserID).From(du).Where(du.DeptID.Eq(3)))).And(u.Name.NotInExpr(Expr("'a'")))),
+			expected: "UPDATE `typed_user` SET `id`=`id`+? WHERE `id` = ? AND `id` != ABS(`id`-?) AND `id` > ? AND `id` >= 1 AND `id` < 9 AND `id` <= 8 " +
+				"AND `id` IN (SELECT `user_id` FROM `typed_dept_user` WHERE `dept_id` = ?) AND `name` NOT IN ('a')",
+			args: []any{int64(1), int64(5), int64(2), 0, int64(3)},
+		},
+		{
+			query:    u.Insert(u.ID.Column, u.Name.Column).Values(Val(int64(1)), PH),
+			expected: "INSERT INTO `typed_user` (`id`, `name`) VALUES (?, ?)",
+			args:     []any{int64(1), "x"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			var args []any
+			if len(test.args) > 0 && test.args[len(test.args)-1] == "x" {
+				args = []any{"x"}
+			}
+			s, err := test.query.Build(args...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s.SQL != test.expected {
+				t.Errorf("got %s, want %s", s.SQL, test.expected)
+			}
+			if !reflect.DeepEqual(s.Args, test.args) {
+				t.Errorf("got %v, want %v", s.Args, test.args)
+			}
+		})
+	}
+
+	if got := Select(u.Columns()).From(u).String(); got != "SELECT `id`, `name`, `createdat`, `note` FROM `typed_user`" {
+		t.Errorf("got %s", got)
+	}
+}
@@ -2,15 +2,43 @@ package sb
 
 import (
 	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"strconv"
 )
 
+var (
+	ErrNoChanges    = errors.New("sb: no changes to update") // UPDATE 语句没有任何赋值，不需要执行
+	ErrNoPrimaryKey = errors.New("sb: no primary key")       // SetChanged 找不到表的主键
+	ErrNoVersion    = errors.New("sb: no version column")    // CheckVersion 的表没有声明版本列
+)
+
+// ConflictError 表示使用乐观锁的 UPDATE 没有更新任何行，即记录已被修改（或不存在）
+type ConflictError struct {
+	Table   string
+	Version any // CheckVersion 传入的版本号
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("sb: version conflict on table %s, version %v", e.Table, e.Version)
+}
+
 type UpdateQuery struct {
-	table       AnyTable
-	assignments Assignments
-	where       Cond
-	orderBys    OrderBys
-	limit       uint64
+	table        AnyTable
+	assignments  Assignments
+	where        Cond
+	orderBys     OrderBys
+	limit        uint64
+	version      *Value // 由 CheckVersion 设置的当前版本号
+	keys         []Cond // 由 SetChanged 设置的主键条件
+	err          error  // 如 SetChanged 找不到主键、CheckVersion 的表没有版本列，在 Build、StringE 和 Compiled.Args 中返回
+	deleted      DeletedScope
+	tenant       *Value // 由 Tenant 设置的租户
+	interceptors []Interceptor
+	comments     []string
+	hints        []string
 }
 
 func Update(table AnyTable) *UpdateQuery {
@@ -46,13 +74,60 @@ func (q *UpdateQuery) Limit(limit uint64) *UpdateQuery {
 	return q
 }
 
+// CheckVersion 使用乐观锁：SET 中追加 `version`=`version`+1，WHERE 中追加 `version` = ?（参数为 version），
+// 其中 version 列由 sb:"version" tag 或 WithVersionColumn 声明，没有声明时 Build、StringE、Compiled.Args 和 ExecUpdate 返回 ErrNoVersion。
+// 追加的赋值和条件在生成 SQL 时加入，不会被之后的 Set 和 Where 覆盖
+func (q *UpdateQuery) CheckVersion(version any) *UpdateQuery {
+	if meta := q.table.getMeta(); meta == nil || meta.Version == "" {
+		q.err = fmt.Errorf("%w: table %s", ErrNoVersion, q.table.getName())
+		return q
+	}
+	v := Val(version)
+	q.version = &v
+	return q
+}
+
+// versionColumn 返回版本列，没有调用 CheckVersion 时返回 nil
+func (q *UpdateQuery) versionColumn() *Column {
+	if q.version == nil {
+		return nil
+	}
+	return &Column{name: q.table.getMeta().Version}
+}
+
+func (q *UpdateQuery) fullAssignments() Assignments {
+	column := q.versionColumn()
+	if column == nil {
+		return q.assignments
+	}
+	assignments := make(Assignments, len(q.assignments), len(q.assignments)+1)
+	copy(assignments, q.assignments)
+	return append(assignments, column.Assign(column.Plus(Expr("1"))))
+}
+
+func (q *UpdateQuery) fullWhere() Cond {
+	where := q.where
+	for _, key := range q.keys {
+		where = andWhere(where, key)
+	}
+	where = andScope(andTenant(where, q.table, q.tenant), q.table, q.deleted)
+	column := q.versionColumn()
+	if column == nil {
+		return where
+	}
+	return andWhere(where, column.Eq(*q.version))
+}
+
 func (q *UpdateQuery) WriteSQL(buf *bytes.Buffer) {
-	buf.WriteString("UPDATE `")
-	buf.WriteString(q.table.getName())
-	buf.WriteString("` SET ")
-	q.assignments.WriteSQL(buf, NoAlias)
-	if q.where != nil {
-		q.where.WriteSQL(buf, NoAlias)
+	q = q.scoped()
+	writeComments(buf, q.comments)
+	buf.WriteString("UPDATE ")
+	writeHints(buf, q.hints)
+	writeTableName(buf, q.table)
+	buf.WriteString(" SET ")
+	q.fullAssignments().WriteSQL(buf, NoAlias)
+	if where := q.fullWhere(); where != nil {
+		where.WriteSQL(buf, NoAlias)
 	}
 	q.orderBys.WriteSQL(buf, NoAlias)
 	if q.limit > 0 {
@@ -61,13 +136,94 @@ func (q *UpdateQuery) WriteSQL(buf *bytes.Buffer) {
 	}
 }
 
+// String 返回经过 Interceptor 处理的 SQL，StringE 返回错误时返回空字符串，主要用于调试和日志
 func (q *UpdateQuery) String() string {
+	sql, _ := q.StringE()
+	return sql
+}
+
+// StringE 返回经过 Interceptor 处理的 SQL，没有任何赋值时返回 ErrNoChanges，
+// 语句无效、Interceptor 拒绝或严格模式下没有设置租户时同样返回错误
+func (q *UpdateQuery) StringE() (string, error) {
+	if err := q.validate(); err != nil {
+		return "", err
+	}
+	return q.interception().interceptSQL(q.render())
+}
+
+func (q *UpdateQuery) render() string {
 	buf := pool.Get().(*bytes.Buffer)
 	buf.Reset()
 
 	q.WriteSQL(buf)
 
-	sql := buf.String()
+	sql := sqlString(buf)
 	pool.Put(buf)
 	return sql
 }
+
+// IsNoop 在没有任何赋值时返回 true，如 SetChanged 没有发现变化的字段
+func (q *UpdateQuery) IsNoop() bool {
+	return len(q.assignments) == 0
+}
+
+// Build 返回 SQL 和参数，args 会按顺序填充未绑定值的 ?，sql.NamedArg 会按名字绑定 Param；
+// 没有任何赋值时返回 ErrNoChanges
+func (q *UpdateQuery) Build(args ...any) (Statement, error) {
+	if err := q.validate(); err != nil {
+		return Statement{}, err
+	}
+	slots := q.appendArgs(nil)
+	bound, err := bindArgs(slots[:0], slots, args)
+	if err != nil {
+		return Statement{}, err
+	}
+	sql, bound, err := q.interception().intercept(q.render(), bound)
+	if err != nil {
+		return Statement{}, err
+	}
+	return Statement{SQL: sql, Args: bound}, nil
+}
+
+// Compile 生成 SQL 并返回可以反复绑定参数的 Compiled，之后对 q 的修改不会影响它；
+// 没有任何赋值时 Compiled 的 Args 和 Bind 返回 ErrNoChanges
+func (q *UpdateQuery) Compile() *Compiled {
+	return newCompiled(q.render(), q.appendArgs(nil), q.validate(), q.interception())
+}
+
+// validate 返回语句无法执行的原因，如 SetChanged 找不到主键、表没有版本列、没有任何赋值、严格模式下没有设置租户
+func (q *UpdateQuery) validate() error {
+	if q.err != nil {
+		return q.err
+	}
+	if q.IsNoop() {
+		return ErrNoChanges
+	}
+	return q.checkTenant()
+}
+
+func (q *UpdateQuery) appendArgs(args []any) []any {
+	q = q.scoped()
+	args = q.fullAssignments().appendArgs(args)
+	return appendArgs(args, q.fullWhere())
+}
+
+// ExecUpdate 执行 UPDATE 语句，调用过 CheckVersion 且没有更新任何行时返回 *ConflictError
+func ExecUpdate(ctx context.Context, db Execer, q *UpdateQuery, args ...any) (sql.Result, error) {
+	s, err := q.Build(args...)
+	if err != nil {
+		return nil, err
+	}
+	result, err := db.ExecContext(ctx, s.SQL, s.Args...)
+	if err != nil || q.version == nil {
+		return result, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return result, err
+	}
+	if affected == 0 {
+		return result, &ConflictError{Table: q.table.getName(), Version: q.version.value}
+	}
+	return result, nil
+}
@@ -1,6 +1,11 @@
 package sb
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
 
 func TestUpdateQuery(t *testing.T) {
 	u := New[UserTable]("u")
@@ -35,3 +40,134 @@ func TestUpdateQuery(t *testing.T) {
 		})
 	}
 }
+
+type ArticleTable struct {
+	Table   `db:"article"`
+	ID      Column `db:"id" ddl:"type:bigint;primary key"`
+	Title   Column `db:"title"`
+	Version Column `db:"version" sb:"version"`
+}
+
+type DocumentTable struct {
+	Table `db:"document"`
+	ID    Column `db:"id" ddl:"type:bigint;primary key"`
+	Rev   Column `db:"rev"`
+}
+
+type articleRow struct {
+	ID      int64  `db:"id"`
+	Title   string `db:"title"`
+	Version int    `db:"version"`
+}
+
+func TestUpdateCheckVersion(t *testing.T) {
+	a := New[ArticleTable]("a")
+	RegisterTable[DocumentTable](WithVersionColumn("rev"))
+	d := New[DocumentTable]("d")
+
+	tests := []struct {
+		query    *UpdateQuery
+		expected string
+		args     []any
+	}{
+		{
+			query:    a.Update(a.Title.Assign(Val("t"))).CheckVersion(3),
+			expected: "UPDATE `article` SET `title`=?, `version`=`version`+1 WHERE `version` = ?",
+			args:     []any{"t", 3},
+		},
+		{
+			// CheckVersion 之后调用 Where 不会覆盖版本条件
+			query:    a.Update().CheckVersion(3).Set(a.Title.Assign(Val("t"))).Where(a.ID.Eq(Val(1))),
+			expected: "UPDATE `article` SET `title`=?, `version`=`version`+1 WHERE `id` = ? AND `version` = ?",
+			args:     []any{"t", 1, 3},
+		},
+		{
+			query:    d.Update(d.Rev.Assign(Val(9))).Where(d.ID.Eq(Val(1))).CheckVersion(2),
+			expected: "UPDATE `document` SET `rev`=?, `rev`=`rev`+1 WHERE `id` = ? AND `rev` = ?",
+			args:     []any{9, 1, 2},
+		},
+		{
+			// 版本列不会出现在 SET 中
+			query:    a.Update().SetStruct(articleRow{ID: 1, Title: "t", Version: 3}, OmitPrimaryKey()).Where(a.ID.Eq(Val(1))),
+			expected: "UPDATE `article` SET `title`=?, `version`=`version`+1 WHERE `id` = ? AND `version` = ?",
+			args:     []any{"t", 1, 3},
+		},
+		{
+			query:    a.Update().SetChanged(articleRow{ID: 1, Title: "a", Version: 3}, articleRow{ID: 1, Title: "b", Version: 4}),
+			expected: "UPDATE `article` SET `title`=?, `version`=`version`+1 WHERE `id` = ? AND `version` = ?",
+			args:     []any{"b", int64(1), 3},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			s, err := test.query.Build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s.SQL != test.expected {
+				t.Errorf("got %s, want %s", s.SQL, test.expected)
+			}
+			if !reflect.DeepEqual(s.Args, test.args) {
+				t.Errorf("got %v, want %v", s.Args, test.args)
+			}
+		})
+	}
+
+	noop := a.Update().SetChanged(articleRow{ID: 1, Version: 3}, articleRow{ID: 1, Version: 4})
+	if _, err := noop.Build(); err != ErrNoChanges {
+		t.Errorf("got %v, want ErrNoChanges", err)
+	}
+	if _, err := noop.Compile().Args(); err != ErrNoChanges {
+		t.Errorf("got %v, want ErrNoChanges", err)
+	}
+	if _, err := noop.Compile().Bind(); err != ErrNoChanges {
+		t.Errorf("got %v, want ErrNoChanges", err)
+	}
+	if _, err := noop.StringE(); err != ErrNoChanges {
+		t.Errorf("got %v, want ErrNoChanges", err)
+	}
+	if got := noop.String(); got != "" {
+		t.Errorf("got %q, want empty", got)
+	}
+
+	// 表没有版本列时返回错误
+	u := New[UserTable]("u")
+	noVersion := u.Update(u.Name.Assign(PH)).CheckVersion(1)
+	if _, err := noVersion.Build("n"); !errors.Is(err, ErrNoVersion) {
+		t.Errorf("got %v, want ErrNoVersion", err)
+	}
+	if _, err := noVersion.Compile().Args("n"); !errors.Is(err, ErrNoVersion) {
+		t.Errorf("got %v, want ErrNoVersion", err)
+	}
+	if _, err := ExecUpdate(context.Background(), nil, noVersion, "n"); !errors.Is(err, ErrNoVersion) {
+		t.Errorf("got %v, want ErrNoVersion", err)
+	}
+}
+
+func TestExecUpdate(t *testing.T) {
+	a := New[ArticleTable]("a")
+	fake := newFakeDB()
+	db := fake.open()
+	defer db.Close()
+
+	q := a.Update(a.Title.Assign(PH)).Where(a.ID.Eq(Val(1))).CheckVersion(3)
+	fake.set(q.String(), fakeResult{rowsAffected: 0})
+	_, err := ExecUpdate(context.Background(), db, q, "t")
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) || conflict.Table != "article" || conflict.Version != 3 {
+		t.Fatalf("got %v, want ConflictError", err)
+	}
+
+	fake.set(q.String(), fakeResult{rowsAffected: 1})
+	if _, err := ExecUpdate(context.Background(), db, q, "t"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 没有使用乐观锁时不检查影响的行数
+	q = a.Update(a.Title.Assign(PH))
+	fake.set(q.String(), fakeResult{rowsAffected: 0})
+	if _, err := ExecUpdate(context.Background(), db, q, "t"); err != nil {
+		t.Fatal(err)
+	}
+}